@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/caravelcommerce/deck/internal/certs"
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/traefik"
+	"github.com/spf13/cobra"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage the local certificate authority",
+	Long: `Deck issues the HTTPS certificates of every project from a local certificate
+authority. Trust it once with 'deck certs trust' and browsers accept all of them.`,
+}
+
+var certsTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Install the local CA into the system and browser trust stores",
+	RunE:  runCertsTrust,
+}
+
+var certsUntrustCmd = &cobra.Command{
+	Use:   "untrust",
+	Short: "Remove the local CA from the system and browser trust stores",
+	RunE:  runCertsUntrust,
+}
+
+var certsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the local CA, where it is trusted and the project certificates",
+	RunE:  runCertsStatus,
+}
+
+func init() {
+	certsCmd.AddCommand(certsTrustCmd)
+	certsCmd.AddCommand(certsUntrustCmd)
+	certsCmd.AddCommand(certsStatusCmd)
+}
+
+func runCertsTrust(cmd *cobra.Command, args []string) error {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	ca, created, err := certs.LoadOrCreateCA(global.Certificates.KeyType)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Printf("✅ Created local CA at %s\n", ca.CertPath)
+	}
+
+	stores := certs.Stores()
+	if len(stores) == 0 {
+		fmt.Println("⚠️  Warning: no supported trust store found.")
+		fmt.Printf("Import %s manually into your system or browser.\n", ca.CertPath)
+		return nil
+	}
+
+	failed := 0
+	for _, store := range stores {
+		if store.Installed(ca) {
+			fmt.Printf("✅ Already trusted by %s\n", store.Name())
+			continue
+		}
+		fmt.Printf("📝 Installing local CA into %s...\n", store.Name())
+		if err := store.Install(ca); err != nil {
+			fmt.Printf("⚠️  Warning: %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ Trusted by %s\n", store.Name())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to install the local CA into %d trust store(s)", failed)
+	}
+
+	fmt.Println("\nRestart your browser to pick up the new certificate authority.")
+	return nil
+}
+
+func runCertsUntrust(cmd *cobra.Command, args []string) error {
+	ca, err := certs.LoadCA()
+	if err != nil {
+		return fmt.Errorf("no local CA found: %w", err)
+	}
+
+	failed := 0
+	for _, store := range certs.Stores() {
+		if !store.Installed(ca) {
+			continue
+		}
+		fmt.Printf("📝 Removing local CA from %s...\n", store.Name())
+		if err := store.Uninstall(ca); err != nil {
+			fmt.Printf("⚠️  Warning: %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ Removed from %s\n", store.Name())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to remove the local CA from %d trust store(s)", failed)
+	}
+	return nil
+}
+
+func runCertsStatus(cmd *cobra.Command, args []string) error {
+	ca, err := certs.LoadCA()
+	if err != nil {
+		fmt.Println("No local CA yet. It is created by 'deck setup' or 'deck certs trust'.")
+		return nil
+	}
+
+	fmt.Printf("Local CA:    %s\n", ca.CertPath)
+	fmt.Printf("Subject:     %s\n", ca.Cert.Subject.CommonName)
+	fmt.Printf("Key:         %s\n", ca.Cert.PublicKeyAlgorithm)
+	fmt.Printf("Expires:     %s\n", ca.Cert.NotAfter.Format("2006-01-02"))
+	fmt.Printf("Fingerprint: SHA256 %s\n", certs.Fingerprint(ca.Cert))
+
+	fmt.Println("\nTrust stores:")
+	stores := certs.Stores()
+	if len(stores) == 0 {
+		fmt.Println("  (no supported trust store found)")
+	}
+	for _, store := range stores {
+		status := "not trusted"
+		if store.Installed(ca) {
+			status = "trusted"
+		}
+		fmt.Printf("  %-12s %s\n", status, store.Name())
+	}
+
+	traefikDir, err := traefik.GetTraefikDir()
+	if err != nil {
+		return err
+	}
+	paths, _ := filepath.Glob(filepath.Join(traefikDir, "certs", "*-cert.pem"))
+	if len(paths) == 0 {
+		return nil
+	}
+
+	fmt.Println("\nProject certificates:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "  PROJECT\tKEY\tEXPIRES\tSTATUS\tHOSTS")
+	for _, path := range paths {
+		cert, err := certs.ReadCertificate(path)
+		if err != nil {
+			continue
+		}
+		status := "ok"
+		switch {
+		case cert.CheckSignatureFrom(ca.Cert) != nil:
+			status = "other CA"
+		case time.Until(cert.NotAfter) < 0:
+			status = "expired"
+		case time.Until(cert.NotAfter) < certs.RenewBefore:
+			status = "renewal due"
+		}
+		project := strings.TrimSuffix(filepath.Base(path), "-cert.pem")
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", project, cert.PublicKeyAlgorithm, cert.NotAfter.Format("2006-01-02"), status, strings.Join(cert.DNSNames, ", "))
+	}
+	return w.Flush()
+}
+
+// caTrusted informa se a CA local já está instalada em algum trust store
+func caTrusted() bool {
+	ca, err := certs.LoadCA()
+	if err != nil {
+		return false
+	}
+	for _, store := range certs.Stores() {
+		if store.Installed(ca) {
+			return true
+		}
+	}
+	return false
+}
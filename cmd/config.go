@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/docker"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect, validate and migrate deck.yaml",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate deck.yaml",
+	Long:  `Checks deck.yaml and deck.local.yaml for unknown keys, wrong types, invalid project names and unknown versions.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runConfigValidate,
+	// Validation errors already explain what is wrong
+	SilenceUsage: true,
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for deck.yaml",
+	Long: `Prints the JSON Schema for deck.yaml. Editors using the YAML language server pick it up
+from the '# yaml-language-server: $schema=...' comment at the top of deck.yaml.`,
+	RunE: runConfigSchema,
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [file]",
+	Short: "Rewrite deck.yaml in the canonical format",
+	Long: `Rewrites shorthand keys (name, php: 8.3, openswoole, swoole_port) into the canonical
+nested format, preserving comments. A copy of the original file is kept as <file>.bak.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigMigrate,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the fully resolved configuration",
+	Long: `Prints the effective configuration after merging deck.local.yaml and applying the
+Magento compatibility matrix and built-in defaults. Each value is annotated with where
+it came from.`,
+	RunE: runConfigShow,
+}
+
+var (
+	configMigrateDryRun bool
+	configShowJSON      bool
+	configShowYAML      bool
+)
+
+func init() {
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRun, "dry-run", false, "Show the changes without writing the file")
+
+	configShowCmd.Flags().BoolVar(&configShowJSON, "json", false, "Output as JSON")
+	configShowCmd.Flags().BoolVar(&configShowYAML, "yaml", false, "Output as YAML with source comments (default)")
+	configShowCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	path := "deck.yaml"
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	// Loading validates deck.yaml and deck.local.yaml after interpolating variables
+	if _, err := config.LoadConfig(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ %s is valid\n", path)
+	return nil
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadConfig("deck.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if !configShowJSON {
+		data, err := cfg.AnnotatedYAML()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	// Round-trip through YAML so the JSON keys match deck.yaml
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	var resolved map[string]interface{}
+	if err := yaml.Unmarshal(data, &resolved); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]interface{}{
+		"config":  resolved,
+		"sources": cfg.Sources(),
+	})
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	path := "deck.yaml"
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	migrated, changed, err := config.Migrate(data)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		fmt.Printf("✅ %s is already in the canonical format\n", path)
+		return nil
+	}
+
+	fmt.Print(docker.UnifiedDiff(filepath.Base(path), data, migrated))
+	if configMigrateDryRun {
+		return nil
+	}
+
+	if err := os.WriteFile(path+".bak", data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("\n✅ %s migrated (original saved as %s.bak)\n", path, path)
+	return nil
+}
+
+func runConfigSchema(cmd *cobra.Command, args []string) error {
+	_, err := os.Stdout.Write(config.Schema)
+	return err
+}
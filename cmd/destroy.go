@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/registry"
+	"github.com/caravelcommerce/deck/internal/traefik"
+	"github.com/spf13/cobra"
+)
+
+var destroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Remove containers, volumes, images and the .deck directory",
+	Long: `Completely removes the Docker environment for the Magento project: containers,
+named volumes (database, search, cache and queue data), locally built images and
+the .deck directory. The project is also removed from the registry.`,
+	RunE: runDestroy,
+}
+
+var (
+	destroyProject string
+	destroyForce   bool
+)
+
+func init() {
+	destroyCmd.Flags().StringVarP(&destroyProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+	destroyCmd.Flags().BoolVarP(&destroyForce, "force", "f", false, "Skip the confirmation prompt")
+}
+
+func runDestroy(cmd *cobra.Command, args []string) error {
+	projectDir, cfg, err := loadProject(destroyProject)
+	if err != nil {
+		return err
+	}
+	deckDir := filepath.Join(projectDir, ".deck")
+
+	fmt.Printf("⚠️  This will permanently delete all data for: %s\n", cfg.Project)
+	fmt.Println("   • Containers and networks")
+	fmt.Printf("   • Volumes: %s\n", strings.Join(dataVolumes, ", "))
+	fmt.Println("   • Locally built images")
+	fmt.Printf("   • %s\n", deckDir)
+	if !destroyForce && !askConfirmation("Are you sure?") {
+		fmt.Println("Destroy cancelled.")
+		return nil
+	}
+
+	// Run docker compose down removing volumes and built images
+	if err := runCompose(deckDir, "down", "--volumes", "--rmi", "local", "--remove-orphans"); err != nil {
+		return fmt.Errorf("failed to remove Docker environment: %w", err)
+	}
+
+	if err := os.RemoveAll(deckDir); err != nil {
+		return fmt.Errorf("failed to remove .deck directory: %w", err)
+	}
+
+	if err := traefik.RemoveProjectCertificate(cfg.Project); err != nil {
+		fmt.Printf("⚠️  Warning: failed to remove project certificate: %v\n", err)
+	}
+
+	// Remove the project from the registry
+	reg, err := registry.Load()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load project registry: %v\n", err)
+	} else if reg.Remove(cfg.Project) {
+		if err := reg.Save(); err != nil {
+			fmt.Printf("⚠️  Warning: failed to update project registry: %v\n", err)
+		} else {
+			syncHostsFile()
+		}
+	}
+
+	// Recreate a running Traefik so it releases the TCP ports of the project
+	if traefik.IsTraefikRunning() {
+		global, err := config.LoadGlobalConfig()
+		if err == nil {
+			err = ensureProxy(global)
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Warning: failed to update Traefik: %v\n", err)
+		}
+	}
+
+	fmt.Println("✅ Environment destroyed successfully!")
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/dns"
+	"github.com/caravelcommerce/deck/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Manage local name resolution for project hostnames",
+	Long: `Deck keeps a marker-delimited block in /etc/hosts pointing the hostnames of
+every registered project to 127.0.0.1. 'deck setup' and 'deck destroy' keep it in
+sync; set dns.manage_hosts: false in the global config to manage DNS yourself.`,
+}
+
+var dnsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Write the hostnames of all registered projects to /etc/hosts",
+	RunE:  runDNSSync,
+}
+
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether project hostnames resolve locally",
+	RunE:  runDNSStatus,
+}
+
+var dnsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the Deck block from /etc/hosts",
+	RunE:  runDNSRemove,
+}
+
+var dnsResolverCmd = &cobra.Command{
+	Use:   "resolver",
+	Short: "Show how to resolve all *.test hostnames with dnsmasq or systemd-resolved",
+	Run:   runDNSResolver,
+}
+
+func init() {
+	dnsCmd.AddCommand(dnsSyncCmd)
+	dnsCmd.AddCommand(dnsStatusCmd)
+	dnsCmd.AddCommand(dnsRemoveCmd)
+	dnsCmd.AddCommand(dnsResolverCmd)
+}
+
+func runDNSSync(cmd *cobra.Command, args []string) error {
+	hosts, err := registeredHosts()
+	if err != nil {
+		return err
+	}
+
+	changed, err := dns.Sync(hosts)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		fmt.Printf("✅ Updated %s with %d hostname(s)\n", dns.HostsFile, len(hosts))
+	} else {
+		fmt.Printf("✅ %s is up to date\n", dns.HostsFile)
+	}
+	return nil
+}
+
+func runDNSStatus(cmd *cobra.Command, args []string) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return err
+	}
+
+	managed, err := dns.ManagedHosts()
+	if err != nil {
+		return err
+	}
+	inBlock := map[string]bool{}
+	for _, host := range managed {
+		inBlock[host] = true
+	}
+
+	if len(reg.Projects) == 0 {
+		fmt.Println("No projects registered yet. Run 'deck setup' in a project directory.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tHOSTNAME\tHOSTS FILE\tRESOLVES")
+	for _, project := range reg.Projects {
+		for _, host := range project.Domains {
+			hostsFile := "-"
+			if inBlock[host] {
+				hostsFile = "yes"
+			}
+			resolves := "no"
+			if dns.Resolves(host) {
+				resolves = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", project.Name, host, hostsFile, resolves)
+		}
+	}
+	return w.Flush()
+}
+
+func runDNSRemove(cmd *cobra.Command, args []string) error {
+	changed, err := dns.Remove()
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		fmt.Printf("✅ Removed the Deck block from %s\n", dns.HostsFile)
+	} else {
+		fmt.Printf("No Deck block found in %s\n", dns.HostsFile)
+	}
+	return nil
+}
+
+func runDNSResolver(cmd *cobra.Command, args []string) {
+	fmt.Print(`A wildcard resolver makes every *.test hostname resolve to 127.0.0.1, so new
+projects and subdomains work without touching /etc/hosts. Afterwards you can set
+"dns: {manage_hosts: false}" in ` + globalConfigPathHint() + `.
+
+dnsmasq (Linux):
+  echo 'address=/test/127.0.0.1' | sudo tee /etc/dnsmasq.d/deck-test.conf
+  sudo systemctl restart dnsmasq
+
+systemd-resolved with dnsmasq listening on 127.0.0.2 (Linux):
+  printf 'listen-address=127.0.0.2\nbind-interfaces\naddress=/test/127.0.0.1\n' | sudo tee /etc/dnsmasq.d/deck-test.conf
+  sudo mkdir -p /etc/systemd/resolved.conf.d
+  printf '[Resolve]\nDNS=127.0.0.2\nDomains=~test\n' | sudo tee /etc/systemd/resolved.conf.d/deck-test.conf
+  sudo systemctl restart dnsmasq systemd-resolved
+
+macOS (dnsmasq from Homebrew):
+  brew install dnsmasq
+  echo 'address=/test/127.0.0.1' >> $(brew --prefix)/etc/dnsmasq.conf
+  sudo brew services start dnsmasq
+  sudo mkdir -p /etc/resolver
+  echo 'nameserver 127.0.0.1' | sudo tee /etc/resolver/test
+
+Hostnames outside .test (custom domains in deck.yaml) still need the hosts file
+or an equivalent dnsmasq address= line.
+`)
+}
+
+// registeredHosts retorna os hostnames de todos os projetos registrados e do dashboard do Traefik
+func registeredHosts() ([]string, error) {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var hosts []string
+	if global.DashboardEnabled() {
+		hosts = append(hosts, global.Traefik.Dashboard.Host)
+	}
+	for _, project := range reg.Projects {
+		hosts = append(hosts, project.Domains...)
+	}
+	return hosts, nil
+}
+
+// syncHostsFile atualiza o bloco do Deck no /etc/hosts após mudanças no registro,
+// a menos que o usuário tenha desativado dns.manage_hosts
+func syncHostsFile() {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+		return
+	}
+	if !global.ManagesHosts() {
+		return
+	}
+
+	hosts, err := registeredHosts()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+		return
+	}
+
+	changed, err := dns.Sync(hosts)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+		fmt.Println("   Run 'deck dns sync' to retry or 'deck dns resolver' for alternatives.")
+		return
+	}
+	if changed {
+		fmt.Printf("✅ Updated %s\n", dns.HostsFile)
+	}
+}
+
+// globalConfigPathHint retorna o caminho da configuração global para mensagens ao usuário
+func globalConfigPathHint() string {
+	path, err := config.GlobalConfigPath()
+	if err != nil {
+		return config.GlobalConfigFile
+	}
+	return path
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Remove the Docker containers, keeping volumes",
+	Long:  `Stops and removes all Docker containers and networks for the Magento project. Database and search data volumes are kept.`,
+	RunE:  runDown,
+}
+
+var downProject string
+
+func init() {
+	downCmd.Flags().StringVarP(&downProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+}
+
+func runDown(cmd *cobra.Command, args []string) error {
+	projectDir, cfg, err := loadProject(downProject)
+	if err != nil {
+		return err
+	}
+	deckDir := filepath.Join(projectDir, ".deck")
+
+	fmt.Printf("🛑 Removing Docker containers for: %s\n", cfg.Project)
+
+	// Run docker compose down (volumes are kept)
+	if err := runCompose(deckDir, "down", "--remove-orphans"); err != nil {
+		return fmt.Errorf("failed to remove Docker containers: %w", err)
+	}
+
+	fmt.Println("✅ Containers removed successfully! Volumes were kept.")
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var hyvaCmd = &cobra.Command{
+	Use:   "hyva",
+	Short: "Build Hyvä theme styles with Tailwind",
+	Long: `Runs the Tailwind build of Hyvä themes in a Node.js container, so Node does not
+need to be installed on the host. Themes are found in app/design/frontend/*/*/web/tailwind
+(or the default theme in vendor/hyva-themes). The Node.js version follows the node
+setting in deck.yaml.`,
+}
+
+var hyvaBuildCmd = &cobra.Command{
+	Use:   "build [Vendor/theme]",
+	Short: "Build the theme styles for production",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHyva(hyvaProject, args, "build", "build-prod")
+	},
+}
+
+var hyvaWatchCmd = &cobra.Command{
+	Use:   "watch [Vendor/theme]",
+	Short: "Rebuild the theme styles whenever templates change",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHyva(hyvaProject, args, "watch")
+	},
+}
+
+var hyvaProject string
+
+func init() {
+	hyvaCmd.PersistentFlags().StringVarP(&hyvaProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+
+	hyvaCmd.AddCommand(hyvaBuildCmd)
+	hyvaCmd.AddCommand(hyvaWatchCmd)
+}
+
+// runHyva executa, no container node, o primeiro script do package.json do tema encontrado entre os candidatos
+func runHyva(projectName string, args []string, scripts ...string) error {
+	projectDir, _, err := loadProject(projectName)
+	if err != nil {
+		return err
+	}
+	deckDir := filepath.Join(projectDir, ".deck")
+
+	if !config.DetectHyva(projectDir) {
+		return fmt.Errorf("no Hyvä packages found in composer.lock or composer.json")
+	}
+	if err := requireNodeService(deckDir); err != nil {
+		return err
+	}
+
+	theme, err := selectHyvaTheme(projectDir, args)
+	if err != nil {
+		return err
+	}
+	themeDir := filepath.Join(projectDir, filepath.FromSlash(theme.Dir))
+
+	script, err := packageScript(themeDir, scripts...)
+	if err != nil {
+		return err
+	}
+
+	// Dependencies are installed on the first run only
+	install := "npm install"
+	if _, err := os.Stat(filepath.Join(themeDir, "package-lock.json")); err == nil {
+		install = "npm ci"
+	}
+	shell := fmt.Sprintf("{ [ -d node_modules ] || %s; } && npm run %s", install, script)
+
+	fmt.Printf("🎨 Running 'npm run %s' for %s...\n", script, theme.Name)
+	return runCompose(deckDir, "run", "--rm", "-w", "/var/www/html/"+theme.Dir, "node", "sh", "-c", shell)
+}
+
+// requireNodeService verifica se o docker-compose.yml do .deck tem o serviço node
+func requireNodeService(deckDir string) error {
+	compose, err := os.ReadFile(filepath.Join(deckDir, "docker-compose.yml"))
+	if err != nil {
+		return fmt.Errorf("failed to read docker-compose.yml: %w", err)
+	}
+	if !strings.Contains(string(compose), "\n  node:\n") {
+		return fmt.Errorf("the node service is missing from .deck. Set node (e.g. node: 20) in deck.yaml and run 'deck setup'")
+	}
+	return nil
+}
+
+// selectHyvaTheme escolhe o tema informado ou, sem argumento, o único tema do projeto
+func selectHyvaTheme(projectDir string, args []string) (*config.HyvaTheme, error) {
+	themes, err := config.FindHyvaThemes(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Hyvä themes: %w", err)
+	}
+	if len(themes) == 0 {
+		return nil, fmt.Errorf("no Hyvä theme found (expected app/design/frontend/<Vendor>/<theme>/web/tailwind/package.json)")
+	}
+
+	names := make([]string, len(themes))
+	for i, theme := range themes {
+		if len(args) == 1 && theme.Name == args[0] {
+			return &theme, nil
+		}
+		names[i] = theme.Name
+	}
+
+	if len(args) == 1 {
+		return nil, fmt.Errorf("theme '%s' not found (themes available: %s)", args[0], strings.Join(names, ", "))
+	}
+	if len(themes) > 1 {
+		return nil, fmt.Errorf("multiple Hyvä themes found, choose one: %s", strings.Join(names, ", "))
+	}
+	return &themes[0], nil
+}
+
+// packageScript retorna o primeiro dos scripts informados que existe no package.json do diretório
+func packageScript(dir string, candidates ...string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read package.json: %w", err)
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", fmt.Errorf("failed to parse package.json: %w", err)
+	}
+
+	for _, name := range candidates {
+		if _, ok := pkg.Scripts[name]; ok {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("package.json in %s has no %s script", dir, strings.Join(candidates, " or "))
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all registered projects",
+	Long:  `Shows every project registered by 'deck setup' with its running state and URLs.`,
+	RunE:  runList,
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return err
+	}
+
+	if len(reg.Projects) == 0 {
+		fmt.Println("No projects registered yet. Run 'deck setup' inside a Magento project.")
+		return nil
+	}
+
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tMAGENTO\tURLS\tPATH")
+
+	for _, project := range reg.Projects {
+		status := "stopped"
+		if _, err := os.Stat(filepath.Join(project.Path, ".deck")); os.IsNotExist(err) {
+			status = "missing"
+		} else if len(runningContainers(project.Name)) > 0 {
+			status = "running"
+		}
+
+		magentoVersion := project.Magento
+		if magentoVersion == "" {
+			magentoVersion = "-"
+		}
+
+		urls := make([]string, 0, len(project.Domains))
+		for _, domain := range project.Domains {
+			urls = append(urls, global.URL(domain))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", project.Name, status, magentoVersion, strings.Join(urls, ", "), project.Path)
+	}
+
+	return w.Flush()
+}
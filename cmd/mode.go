@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/docker"
+	"github.com/caravelcommerce/deck/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var modeCmd = &cobra.Command{
+	Use:   "mode [developer|production|default]",
+	Short: "Show or switch the Magento run mode",
+	Long: `Without arguments, shows the configured Magento mode.
+
+With a mode, runs bin/magento deploy:mode:set inside the PHP container (followed by
+setup:di:compile and setup:static-content:deploy when switching to production),
+saves the mode in deck.local.yaml and updates the nginx and PHP configuration.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: config.MageModes,
+	RunE:      runMode,
+}
+
+var modeProject string
+
+func init() {
+	modeCmd.Flags().StringVarP(&modeProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+}
+
+func runMode(cmd *cobra.Command, args []string) error {
+	projectDir, cfg, err := loadProject(modeProject)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Magento mode: %s (%s)\n", cfg.GetMode(), cfg.Source("mode"))
+		fmt.Printf("PHP profile:  %s (%s)\n", cfg.GetPHPProfile(), cfg.Source("php.profile"))
+		return nil
+	}
+
+	mode := args[0]
+	if !config.IsMageMode(mode) {
+		return fmt.Errorf("invalid mode %q: must be one of %s", mode, strings.Join(config.MageModes, ", "))
+	}
+
+	if !phpRunning(cfg.Project) {
+		return fmt.Errorf("PHP container is not running. Please run 'deck start' first")
+	}
+
+	fmt.Printf("🔀 Switching %s to %s mode...\n", cfg.Project, mode)
+
+	// deploy:mode:set compila e publica os arquivos estáticos por conta própria; em produção isso é
+	// feito em seguida, na ordem recomendada (DI antes dos estáticos)
+	steps := [][]string{{"deploy:mode:set", mode}}
+	if mode == config.MageModeProduction {
+		steps = [][]string{
+			{"deploy:mode:set", mode, "--skip-compilation"},
+			{"setup:di:compile"},
+			{"setup:static-content:deploy", "-f"},
+			{"cache:flush"},
+		}
+	}
+	for _, step := range steps {
+		fmt.Printf("\n▶️  bin/magento %s\n", strings.Join(step, " "))
+		if err := execInPHP(cfg.Project, append([]string{"php", "bin/magento"}, step...)...); err != nil {
+			return fmt.Errorf("failed to run bin/magento %s: %w", step[0], err)
+		}
+	}
+
+	// Persist the mode locally so the team's deck.yaml is not affected
+	localPath := filepath.Join(projectDir, config.LocalConfigFile)
+	data, err := os.ReadFile(localPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", config.LocalConfigFile, err)
+	}
+	data, err = config.SetValue(data, "mode", mode)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(localPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", config.LocalConfigFile, err)
+	}
+	fmt.Printf("\n✅ Saved mode: %s to %s\n", mode, config.LocalConfigFile)
+
+	if err := applyModeConfig(projectDir); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✅ Magento is now in %s mode\n", mode)
+	return nil
+}
+
+// modeFiles arquivos do .deck que dependem do modo do Magento; só precisam de restart dos serviços
+var modeFiles = map[string]bool{
+	"nginx/default.conf": true,
+	"php/php.ini":        true,
+	"php/php-fpm.conf":   true,
+}
+
+// applyModeConfig grava no .deck os arquivos que dependem do modo e reinicia os serviços afetados.
+// As demais alterações pendentes ficam para o 'deck setup', que as exibe antes de aplicar.
+func applyModeConfig(projectDir string) error {
+	cfg, err := config.LoadConfig(filepath.Join(projectDir, "deck.yaml"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	deckDir := filepath.Join(projectDir, ".deck")
+
+	var tcpPorts map[string]int
+	if reg, err := registry.Load(); err == nil {
+		if project := reg.Get(cfg.Project); project != nil {
+			tcpPorts = project.Ports
+		}
+	}
+
+	files, err := docker.RenderDockerFiles(cfg, projectDir, tcpPorts)
+	if err != nil {
+		return fmt.Errorf("failed to generate Docker files: %w", err)
+	}
+	pending, err := docker.PlanChanges(deckDir, files)
+	if err != nil {
+		return fmt.Errorf("failed to compare Docker files: %w", err)
+	}
+
+	var changes []docker.FileChange
+	for _, change := range pending {
+		if modeFiles[change.Path] && !change.Created && !change.Deleted {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) < len(pending) {
+		fmt.Println("⚠️  Other generated files are out of date. Run 'deck setup' to review and apply them.")
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := docker.ApplyChanges(deckDir, changes); err != nil {
+		return fmt.Errorf("failed to write Docker files: %w", err)
+	}
+	fmt.Printf("✅ Updated %d file(s) in .deck\n", len(changes))
+
+	impact, err := docker.AnalyzeImpact(changes)
+	if err != nil {
+		return err
+	}
+	if len(impact.Restart) > 0 {
+		fmt.Printf("🔄 Restarting %s...\n", strings.Join(impact.Restart, ", "))
+		if err := runCompose(deckDir, append([]string{"restart"}, impact.Restart...)...); err != nil {
+			return fmt.Errorf("failed to restart containers: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/registry"
+)
+
+// resolveProjectDir retorna o diretório do projeto: o diretório atual ou,
+// quando um nome é informado, o caminho registrado para esse projeto
+func resolveProjectDir(projectName string) (string, error) {
+	if projectName == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		return cwd, nil
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return "", err
+	}
+
+	project := reg.Get(projectName)
+	if project == nil {
+		return "", fmt.Errorf("project '%s' is not registered. Run 'deck list' to see known projects", projectName)
+	}
+
+	return project.Path, nil
+}
+
+// loadProject resolve o diretório do projeto, verifica o .deck e carrega o deck.yaml
+func loadProject(projectName string) (string, *config.DeckConfig, error) {
+	projectDir, err := resolveProjectDir(projectName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Check if .deck directory exists
+	deckDir := filepath.Join(projectDir, ".deck")
+	if _, err := os.Stat(deckDir); os.IsNotExist(err) {
+		return "", nil, fmt.Errorf(".deck directory not found in %s. Please run 'deck setup' first", projectDir)
+	}
+
+	// Load deck.yaml to get project name
+	cfg, err := config.LoadConfig(filepath.Join(projectDir, "deck.yaml"))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return projectDir, cfg, nil
+}
+
+// runningContainers retorna os containers em execução do projeto
+func runningContainers(project string) []string {
+	checkCmd := exec.Command("docker", "ps", "--filter", fmt.Sprintf("name=^%s_", project), "--format", "{{.Names}}")
+	output, err := checkCmd.Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(output))
+}
+
+// dataVolumes volumes nomeados do docker-compose.yml gerado
+var dataVolumes = []string{"mariadb_data", "opensearch_data", "redis_data", "rabbitmq_data"}
+
+// legacyComposeProject nome do projeto do docker compose em versões anteriores do Deck, derivado
+// do diretório .deck e por isso o mesmo para todos os projetos
+const legacyComposeProject = "deck"
+
+// warnLegacyProject avisa quando existem containers ou volumes do projeto criados por versões
+// anteriores do Deck, já que eles não são migrados automaticamente
+func warnLegacyProject(project string) {
+	if project == legacyComposeProject {
+		return
+	}
+
+	containers, _ := exec.Command("docker", "ps", "-a",
+		"--filter", "label=com.docker.compose.project="+legacyComposeProject,
+		"--filter", fmt.Sprintf("name=^%s_", project),
+		"--format", "{{.Names}}").Output()
+	if names := strings.Fields(string(containers)); len(names) > 0 {
+		fmt.Printf("⚠️  Warning: containers created by an older Deck version are still present (%s).\n", strings.Join(names, ", "))
+		fmt.Printf("   Remove them, keeping their volumes, with: docker compose -p %s down\n", legacyComposeProject)
+	}
+
+	output, err := exec.Command("docker", "volume", "ls", "--format", "{{.Name}}").Output()
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, name := range strings.Fields(string(output)) {
+		existing[name] = true
+	}
+
+	var legacy []string
+	for _, volume := range dataVolumes {
+		if existing[legacyComposeProject+"_"+volume] && !existing[project+"_"+volume] {
+			legacy = append(legacy, volume)
+		}
+	}
+	if len(legacy) == 0 {
+		return
+	}
+
+	fmt.Println("⚠️  Warning: found volumes created by an older Deck version, which named every project \"deck\":")
+	for _, volume := range legacy {
+		fmt.Printf("   • %s_%s (now %s_%s)\n", legacyComposeProject, volume, project, volume)
+	}
+	fmt.Println("   The new volumes start empty. To keep the old data, stop the project and copy each volume, e.g.:")
+	fmt.Printf("   docker volume create %s_%s && docker run --rm -v %s_%s:/from -v %s_%s:/to alpine cp -a /from/. /to/\n",
+		project, legacy[0], legacyComposeProject, legacy[0], project, legacy[0])
+}
+
+// runCompose executa um comando docker compose no diretório .deck do projeto
+func runCompose(deckDir string, args ...string) error {
+	dockerCmd := exec.Command("docker", append([]string{"compose"}, args...)...)
+	dockerCmd.Dir = deckDir
+	dockerCmd.Stdin = os.Stdin
+	dockerCmd.Stdout = os.Stdout
+	dockerCmd.Stderr = os.Stderr
+	return dockerCmd.Run()
+}
+
+// phpRunning informa se o container PHP do projeto está em execução
+func phpRunning(project string) bool {
+	for _, name := range runningContainers(project) {
+		if name == project+"_php" {
+			return true
+		}
+	}
+	return false
+}
+
+// execInPHP executa um comando no container PHP do projeto, que precisa estar em execução
+func execInPHP(project string, args ...string) error {
+	if !phpRunning(project) {
+		return fmt.Errorf("PHP container is not running. Please run 'deck start' first")
+	}
+
+	dockerCmd := exec.Command("docker", append([]string{"exec", project + "_php"}, args...)...)
+	dockerCmd.Stdout = os.Stdout
+	dockerCmd.Stderr = os.Stderr
+	return dockerCmd.Run()
+}
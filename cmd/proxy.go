@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/traefik"
+	"github.com/spf13/cobra"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Manage the shared Traefik reverse proxy",
+	Long: `All projects are served by a single Traefik container (deck_traefik), configured
+from the traefik section of the global config.`,
+}
+
+var proxyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the proxy state and whether its configuration is up to date",
+	RunE:  runProxyStatus,
+}
+
+var proxyStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the proxy",
+	RunE:  runProxyStart,
+}
+
+var proxyStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the proxy (all project URLs become unreachable)",
+	RunE:  runProxyStop,
+}
+
+var proxyRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Recreate the proxy with the current configuration",
+	RunE:  runProxyRestart,
+}
+
+var proxyUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Pull the configured Traefik image and recreate the proxy",
+	Long: `Pulls the image set by traefik.version in the global config and recreates the
+proxy container. Change traefik.version first to move to another Traefik release.`,
+	RunE: runProxyUpgrade,
+}
+
+var proxyLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the proxy logs",
+	RunE:  runProxyLogs,
+}
+
+var (
+	proxyLogsFollow bool
+	proxyLogsTail   string
+)
+
+func init() {
+	proxyLogsCmd.Flags().BoolVarP(&proxyLogsFollow, "follow", "f", false, "Follow log output")
+	proxyLogsCmd.Flags().StringVar(&proxyLogsTail, "tail", "100", "Number of lines to show from the end of the logs (or \"all\")")
+
+	proxyCmd.AddCommand(proxyStatusCmd)
+	proxyCmd.AddCommand(proxyStartCmd)
+	proxyCmd.AddCommand(proxyStopCmd)
+	proxyCmd.AddCommand(proxyRestartCmd)
+	proxyCmd.AddCommand(proxyUpgradeCmd)
+	proxyCmd.AddCommand(proxyLogsCmd)
+}
+
+func runProxyStatus(cmd *cobra.Command, args []string) error {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	status, err := traefik.GetStatus(global)
+	if err != nil {
+		return err
+	}
+
+	state := "stopped"
+	switch {
+	case status.Running:
+		state = "running"
+	case status.Image == "":
+		state = "not created"
+	}
+
+	fmt.Printf("Container:  %s (%s)\n", traefik.ContainerName, state)
+	if status.Image != "" {
+		fmt.Printf("Image:      %s\n", status.Image)
+	}
+	fmt.Printf("Configured: %s\n", status.ConfiguredTag)
+	fmt.Printf("Ports:      %s:%d (http), %s:%d (https)\n",
+		global.Traefik.BindAddress, global.Traefik.HTTPPort, global.Traefik.BindAddress, global.Traefik.HTTPSPort)
+	if global.DashboardEnabled() {
+		fmt.Printf("Dashboard:  %s (user: %s, password: see %s)\n",
+			global.URL(global.Traefik.Dashboard.Host), global.Traefik.Dashboard.User, traefik.DashboardPasswordSource(global))
+	}
+	if status.AppliedBy != "" {
+		fmt.Printf("Applied by: Deck %s\n", status.AppliedBy)
+	}
+
+	if status.Stale() {
+		fmt.Println("\n⚠️  The proxy configuration is out of date:")
+		for _, reason := range status.StaleReasons {
+			fmt.Printf("   - %s\n", reason)
+		}
+		fmt.Println("Run 'deck proxy restart' (or 'deck proxy upgrade' to pull a new image) to apply it.")
+	}
+
+	return nil
+}
+
+func runProxyStart(cmd *cobra.Command, args []string) error {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	if traefik.IsTraefikRunning() {
+		fmt.Println("✅ Traefik is already running")
+		warnStaleProxy(global)
+		return nil
+	}
+
+	fmt.Println("📦 Starting Traefik reverse proxy...")
+	if err := traefik.Start(global); err != nil {
+		return err
+	}
+	fmt.Println("✅ Traefik is running")
+	return nil
+}
+
+func runProxyStop(cmd *cobra.Command, args []string) error {
+	fmt.Println("🛑 Stopping Traefik reverse proxy...")
+	if err := traefik.Stop(); err != nil {
+		return fmt.Errorf("failed to stop Traefik: %w", err)
+	}
+	fmt.Println("✅ Traefik stopped")
+	return nil
+}
+
+func runProxyRestart(cmd *cobra.Command, args []string) error {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🔄 Recreating Traefik reverse proxy...")
+	if err := traefik.Restart(global); err != nil {
+		return err
+	}
+	fmt.Println("✅ Traefik is running")
+	return nil
+}
+
+func runProxyUpgrade(cmd *cobra.Command, args []string) error {
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("⬆️  Upgrading Traefik to traefik:%s...\n", global.Traefik.Version)
+	if err := traefik.Upgrade(global); err != nil {
+		return err
+	}
+	fmt.Println("✅ Traefik upgraded")
+	return nil
+}
+
+func runProxyLogs(cmd *cobra.Command, args []string) error {
+	return traefik.Logs(proxyLogsFollow, proxyLogsTail)
+}
+
+// ensureProxy inicia o Traefik ou, se ele estiver rodando com uma configuração desatualizada
+// (ex: portas TCP de um projeto novo), recria o container
+func ensureProxy(global *config.GlobalConfig) error {
+	if !traefik.IsTraefikRunning() {
+		fmt.Println("📦 Starting Traefik reverse proxy...")
+		if err := traefik.Start(global); err != nil {
+			return err
+		}
+		fmt.Println("✅ Traefik is running")
+		return nil
+	}
+
+	if _, err := traefik.WriteConfig(global); err != nil {
+		return err
+	}
+	status, err := traefik.GetStatus(global)
+	if err != nil {
+		return err
+	}
+	if !status.Stale() {
+		fmt.Println("✅ Traefik is already running")
+		return nil
+	}
+
+	fmt.Println("🔄 Recreating Traefik to apply the updated proxy configuration...")
+	if err := traefik.Restart(global); err != nil {
+		return err
+	}
+	fmt.Println("✅ Traefik is running")
+	return nil
+}
+
+// warnStaleProxy avisa quando o Traefik em execução não reflete a configuração atual
+func warnStaleProxy(global *config.GlobalConfig) {
+	status, err := traefik.GetStatus(global)
+	if err != nil || !status.Stale() {
+		return
+	}
+	fmt.Println("⚠️  Warning: the Traefik configuration is out of date. Run 'deck proxy status' for details")
+	fmt.Println("   and 'deck proxy restart' to apply it.")
+}
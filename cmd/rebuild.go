@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild [service]",
+	Short: "Rebuild a service image and recreate its container",
+	Long: `Rebuilds the image of a service (the PHP image by default) and recreates its
+container. Use it after changing PHP extensions or the PHP version in deck.yaml.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRebuild,
+}
+
+var (
+	rebuildProject string
+	rebuildNoCache bool
+)
+
+func init() {
+	rebuildCmd.Flags().StringVarP(&rebuildProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+	rebuildCmd.Flags().BoolVar(&rebuildNoCache, "no-cache", false, "Do not use cache when building the image")
+}
+
+func runRebuild(cmd *cobra.Command, args []string) error {
+	projectDir, cfg, err := loadProject(rebuildProject)
+	if err != nil {
+		return err
+	}
+	deckDir := filepath.Join(projectDir, ".deck")
+
+	service := "php"
+	if len(args) > 0 {
+		service = args[0]
+	}
+
+	fmt.Printf("🔨 Rebuilding %s for: %s\n", service, cfg.Project)
+
+	if rebuildNoCache {
+		if err := runCompose(deckDir, "build", "--no-cache", service); err != nil {
+			return fmt.Errorf("failed to build %s image: %w", service, err)
+		}
+	}
+
+	// Recreate the container with the new image
+	if err := runCompose(deckDir, "up", "-d", "--build", "--force-recreate", "--no-deps", service); err != nil {
+		return fmt.Errorf("failed to recreate %s container: %w", service, err)
+	}
+
+	fmt.Printf("✅ %s rebuilt successfully!\n", service)
+
+	return nil
+}
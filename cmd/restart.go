@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var restartCmd = &cobra.Command{
+	Use:   "restart [service...]",
+	Short: "Restart the Docker environment or specific services",
+	Long: `Restarts all Docker containers for the Magento project, or only the given services
+(e.g. 'deck restart php nginx'). Configuration files mounted from .deck such as
+php.ini or nginx configs are picked up on restart.`,
+	RunE: runRestart,
+}
+
+var restartProject string
+
+func init() {
+	restartCmd.Flags().StringVarP(&restartProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	projectDir, cfg, err := loadProject(restartProject)
+	if err != nil {
+		return err
+	}
+	deckDir := filepath.Join(projectDir, ".deck")
+
+	if len(args) > 0 {
+		fmt.Printf("🔄 Restarting %s for: %s\n", strings.Join(args, ", "), cfg.Project)
+	} else {
+		fmt.Printf("🔄 Restarting Docker environment for: %s\n", cfg.Project)
+	}
+
+	// Run docker compose restart
+	if err := runCompose(deckDir, append([]string{"restart"}, args...)...); err != nil {
+		return fmt.Errorf("failed to restart Docker containers: %w", err)
+	}
+
+	fmt.Println("✅ Restarted successfully!")
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/caravelcommerce/deck/internal/traefik"
 	"github.com/spf13/cobra"
 )
 
@@ -19,11 +20,26 @@ func Execute() error {
 func SetVersion(v string) {
 	version = v
 	rootCmd.Version = v
+	traefik.DeckVersion = v
 }
 
 func init() {
 	rootCmd.AddCommand(setupCmd)
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(restartCmd)
+	rootCmd.AddCommand(rebuildCmd)
+	rootCmd.AddCommand(downCmd)
+	rootCmd.AddCommand(destroyCmd)
 	rootCmd.AddCommand(binMagentoCmd)
+	rootCmd.AddCommand(modeCmd)
+	rootCmd.AddCommand(hyvaCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(certsCmd)
+	rootCmd.AddCommand(dnsCmd)
+	rootCmd.AddCommand(proxyCmd)
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/caravelcommerce/deck/internal/config"
 	"github.com/caravelcommerce/deck/internal/docker"
+	"github.com/caravelcommerce/deck/internal/registry"
 	"github.com/caravelcommerce/deck/internal/traefik"
 	"github.com/spf13/cobra"
 )
@@ -16,8 +17,15 @@ import (
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Setup Docker environment for the Magento project",
-	Long:  `Reads deck.yaml and generates all Docker configuration files in the .deck folder.`,
-	RunE:  runSetup,
+	Long: `Reads deck.yaml and generates all Docker configuration files in the .deck folder.
+When .deck already exists, only changed files are rewritten after showing a diff.`,
+	RunE: runSetup,
+}
+
+var setupYes bool
+
+func init() {
+	setupCmd.Flags().BoolVarP(&setupYes, "yes", "y", false, "Apply changes to .deck without asking for confirmation")
 }
 
 func runSetup(cmd *cobra.Command, args []string) error {
@@ -30,34 +38,25 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	configPath := filepath.Join(cwd, "deck.yaml")
 	deckDir := filepath.Join(cwd, ".deck")
 
-	// Verifica se o .deck já existe
-	if _, err := os.Stat(deckDir); err == nil {
-		fmt.Println("⚠️  The .deck directory already exists.")
-		if !askConfirmation("Do you want to overwrite it?") {
-			fmt.Println("Setup cancelled.")
-			return nil
-		}
-		fmt.Println("Removing existing .deck directory...")
-		if err := os.RemoveAll(deckDir); err != nil {
-			return fmt.Errorf("failed to remove .deck directory: %w", err)
-		}
-	}
-
 	// Verifica se o deck.yaml existe
 	var cfg *config.DeckConfig
 	if !config.DeckYAMLExists(configPath) {
-		fmt.Println("📋 deck.yaml not found. Attempting to detect Magento version from composer.json...")
+		fmt.Println("📋 deck.yaml not found. Attempting to detect Magento version from composer.lock/composer.json...")
 
 		// Tenta detectar a versão do Magento
-		magentoVersion, err := config.DetectMagentoVersion(cwd)
+		detected, err := config.DetectMagento(cwd)
 		if err != nil {
 			return fmt.Errorf("failed to detect Magento version: %w\n\nPlease create a deck.yaml file manually with the Magento version", err)
 		}
+		magentoVersion := detected.Version
 
-		fmt.Printf("✅ Detected Magento version: %s\n", magentoVersion)
+		fmt.Printf("✅ Detected %s %s (%s %s in %s)\n", detected.Edition, magentoVersion, detected.Package, detected.Found, detected.Source)
+		if detected.Warning != "" {
+			fmt.Printf("⚠️  Warning: %s\n", detected.Warning)
+		}
 
 		// Obtém o nome do projeto do diretório atual
-		projectName := filepath.Base(cwd)
+		projectName := config.SanitizeProjectName(filepath.Base(cwd))
 
 		fmt.Printf("📝 Creating deck.yaml with project name '%s' and Magento version '%s'...\n", projectName, magentoVersion)
 
@@ -75,6 +74,11 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("🚀 Setting up Deck environment for project: %s\n", cfg.Project)
 
 	// Display configuration
@@ -96,37 +100,66 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	if cfg.IsNodeEnabled() {
 		fmt.Printf("   • Node.js: %s\n", cfg.GetNodeVersion())
 	}
+	if config.DetectHyva(cwd) {
+		fmt.Printf("   • Hyvä: detected (build styles with 'deck hyva build', Node.js %s)\n", cfg.GetNodeImageVersion())
+	}
+	if cfg.IsPWAEnabled() {
+		fmt.Printf("   • PWA Studio: %s (%s)\n", cfg.PWA.Path, global.URL(cfg.Subdomain("pwa")))
+	}
 	if cfg.IsSwooleEnabled() {
 		fmt.Println("   • Swoole: enabled")
 		if cfg.GetSwoolePort() > 0 {
-			fmt.Printf("     API: https://api.%s.test (port %d)\n", cfg.Project, cfg.GetSwoolePort())
+			fmt.Printf("     API: %s (port %d)\n", global.URL(cfg.Subdomain("api")), cfg.GetSwoolePort())
 		}
 	}
 	fmt.Println()
 
-	// Setup Traefik if not running
-	if !traefik.IsTraefikRunning() {
-		fmt.Println("📦 Setting up Traefik reverse proxy...")
-		if err := traefik.SetupTraefik(); err != nil {
-			return fmt.Errorf("failed to setup Traefik: %w", err)
-		}
-		fmt.Println("✅ Traefik is running")
-	} else {
-		fmt.Println("✅ Traefik is already running")
+	// Generate the project certificate covering all of its hostnames
+	if err := traefik.EnsureProjectCertificate(cfg.Project, cfg.GetHostnames()); err != nil {
+		return fmt.Errorf("failed to generate project certificate: %w", err)
 	}
 
-	// Create .deck directory
-	if err := os.MkdirAll(deckDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .deck directory: %w", err)
+	// Allocate the host ports through which Traefik exposes the TCP services
+	reg, err := registry.Load()
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to load project registry: %v\n", err)
+	}
+	var tcpPorts map[string]int
+	if reg != nil {
+		tcpPorts, err = reg.AllocatePorts(cfg.Project, cfg.ExposedServices(), func(port int) bool {
+			return traefik.PortAvailable(global, port)
+		})
+		if err != nil {
+			fmt.Printf("⚠️  Warning: failed to allocate TCP ports: %v\n", err)
+		}
 	}
 
-	// Generate Docker files
+	// Render Docker files in memory and compare with the existing .deck
 	fmt.Println("📝 Generating Docker configuration files...")
-	if err := docker.GenerateDockerFiles(cfg, deckDir); err != nil {
+	files, err := docker.RenderDockerFiles(cfg, cwd, tcpPorts)
+	if err != nil {
 		return fmt.Errorf("failed to generate Docker files: %w", err)
 	}
 
-	// Add .deck to .gitignore if it exists
+	changes, err := docker.PlanChanges(deckDir, files)
+	if err != nil {
+		return fmt.Errorf("failed to compare Docker files: %w", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("✅ .deck is already up to date")
+	} else {
+		applied, err := applySetupChanges(deckDir, changes)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			fmt.Println("Setup cancelled.")
+			return nil
+		}
+	}
+
+	// Add .deck and deck.local.yaml to .gitignore if it exists
 	gitignorePath := filepath.Join(cwd, ".gitignore")
 	if _, err := os.Stat(gitignorePath); err == nil {
 		content, err := os.ReadFile(gitignorePath)
@@ -135,49 +168,126 @@ func runSetup(cmd *cobra.Command, args []string) error {
 			if gitignoreContent == "" || gitignoreContent[len(gitignoreContent)-1] != '\n' {
 				gitignoreContent += "\n"
 			}
-			if !contains(gitignoreContent, ".deck") {
-				gitignoreContent += ".deck/\n"
+			var added []string
+			for _, entry := range []string{".deck/", config.LocalConfigFile} {
+				if !hasGitignoreEntry(gitignoreContent, entry) {
+					gitignoreContent += entry + "\n"
+					added = append(added, entry)
+				}
+			}
+			if len(added) > 0 {
 				if err := os.WriteFile(gitignorePath, []byte(gitignoreContent), 0644); err != nil {
 					fmt.Printf("⚠️  Warning: failed to update .gitignore: %v\n", err)
 				} else {
-					fmt.Println("✅ Added .deck/ to .gitignore")
+					fmt.Printf("✅ Added %s to .gitignore\n", strings.Join(added, ", "))
 				}
 			}
 		}
 	}
 
+	// Register the project in the global registry
+	if reg != nil {
+		if existing := reg.Get(cfg.Project); existing != nil && existing.Path != cwd {
+			fmt.Printf("⚠️  Warning: project name '%s' was registered at %s and now points to %s\n", cfg.Project, existing.Path, cwd)
+		}
+		reg.Register(registry.Project{
+			Name:    cfg.Project,
+			Path:    cwd,
+			Domains: cfg.GetHostnames(),
+			Magento: cfg.Magento,
+			Ports:   tcpPorts,
+		})
+		if err := reg.Save(); err != nil {
+			fmt.Printf("⚠️  Warning: failed to update project registry: %v\n", err)
+		} else {
+			syncHostsFile()
+		}
+	}
+
+	// Start Traefik, or recreate it when the project changed its entrypoints
+	if err := ensureProxy(global); err != nil {
+		return fmt.Errorf("failed to setup Traefik: %w", err)
+	}
+
 	fmt.Println("\n✨ Setup completed successfully!")
-	fmt.Printf("\nYour project will be available at: https://%s.test\n", cfg.Project)
+	fmt.Println("\nYour project will be available at:")
+	for _, host := range cfg.GetDomainHosts() {
+		fmt.Printf("  %s\n", global.URL(host))
+	}
 	if cfg.GetSwoolePort() > 0 {
-		fmt.Printf("Swoole API will be available at: https://api.%s.test\n", cfg.Project)
+		fmt.Printf("Swoole API will be available at: %s\n", global.URL(cfg.Subdomain("api")))
 	}
 	fmt.Println("\nNext steps:")
 	fmt.Println("  1. Run 'deck start' to start the environment")
-	fmt.Printf("  2. Access your site at https://%s.test\n", cfg.Project)
+	fmt.Printf("  2. Access your site at %s\n", global.URL(cfg.GetPrimaryDomain()))
 	if cfg.GetSwoolePort() > 0 {
 		fmt.Printf("  3. Start Swoole server: deck bin/magento swoole:server:start\n")
-		fmt.Printf("  4. Access Swoole API at https://api.%s.test\n", cfg.Project)
+		fmt.Printf("  4. Access Swoole API at %s\n", global.URL(cfg.Subdomain("api")))
 		fmt.Printf("  5. Run 'deck bin/magento' to execute other Magento commands\n")
 	} else {
 		fmt.Println("  3. Run 'deck bin/magento' to execute Magento commands")
 	}
-	fmt.Println("\nNote: You may need to add the SSL certificate to your trusted certificates.")
-
-	traefikDir, err := traefik.GetTraefikDir()
-	if err == nil {
-		fmt.Printf("Certificate location: %s/certs/local-cert.pem\n", traefikDir)
+	if !caTrusted() {
+		fmt.Println("\nNote: run 'deck certs trust' so browsers accept the HTTPS certificates.")
 	}
 
 	return nil
 }
 
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsMiddle(s, substr)))
+// applySetupChanges mostra o diff das alterações, pede confirmação e grava os arquivos alterados
+func applySetupChanges(deckDir string, changes []docker.FileChange) (bool, error) {
+	modified := false
+	for _, change := range changes {
+		if change.Created {
+			fmt.Printf("   + %s (new)\n", change.Path)
+			continue
+		}
+		modified = true
+		fmt.Println()
+		fmt.Print(change.Diff())
+	}
+	fmt.Println()
+
+	if modified && !setupYes && !askConfirmation("Apply these changes to .deck?") {
+		return false, nil
+	}
+
+	if err := docker.ApplyChanges(deckDir, changes); err != nil {
+		return false, fmt.Errorf("failed to write Docker files: %w", err)
+	}
+	fmt.Printf("✅ Updated %d file(s) in .deck\n", len(changes))
+
+	if !modified {
+		return true, nil
+	}
+
+	impact, err := docker.AnalyzeImpact(changes)
+	if err != nil {
+		return false, err
+	}
+	if impact.IsEmpty() {
+		return true, nil
+	}
+
+	fmt.Println("\n🔁 Running containers need to pick up these changes:")
+	if len(impact.Rebuild) > 0 {
+		fmt.Printf("   • Rebuild image: %s (deck rebuild %s)\n", strings.Join(impact.Rebuild, ", "), impact.Rebuild[0])
+	}
+	if len(impact.Recreate) > 0 {
+		fmt.Printf("   • Recreate: %s (deck start)\n", strings.Join(impact.Recreate, ", "))
+	}
+	if len(impact.Restart) > 0 {
+		fmt.Printf("   • Restart: %s (deck restart %s)\n", strings.Join(impact.Restart, ", "), strings.Join(impact.Restart, " "))
+	}
+
+	return true, nil
 }
 
-func containsMiddle(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
+// hasGitignoreEntry verifica se o .gitignore já contém a entrada (com ou sem barra inicial/final)
+func hasGitignoreEntry(content, entry string) bool {
+	entry = strings.Trim(entry, "/")
+	for _, line := range strings.Split(content, "\n") {
+		if strings.Trim(strings.TrimSpace(line), "/") == entry {
 			return true
 		}
 	}
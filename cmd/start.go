@@ -2,8 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/caravelcommerce/deck/internal/config"
@@ -18,62 +16,49 @@ var startCmd = &cobra.Command{
 	RunE:  runStart,
 }
 
+var startProject string
+
+func init() {
+	startCmd.Flags().StringVarP(&startProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+}
+
 func runStart(cmd *cobra.Command, args []string) error {
-	// Get current directory
-	cwd, err := os.Getwd()
+	projectDir, cfg, err := loadProject(startProject)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
+	deckDir := filepath.Join(projectDir, ".deck")
 
-	// Check if .deck directory exists
-	deckDir := filepath.Join(cwd, ".deck")
-	if _, err := os.Stat(deckDir); os.IsNotExist(err) {
-		return fmt.Errorf(".deck directory not found. Please run 'deck setup' first")
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
 	}
 
-	// Load deck.yaml to get project name
-	configPath := filepath.Join(cwd, "deck.yaml")
-	cfg, err := config.LoadConfig(configPath)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	// Ensure Traefik is running with the entrypoints of every project
+	if err := ensureProxy(global); err != nil {
+		return fmt.Errorf("failed to start Traefik: %w", err)
 	}
 
-	// Ensure Traefik is running
-	if !traefik.IsTraefikRunning() {
-		fmt.Println("📦 Starting Traefik reverse proxy...")
-		if err := traefik.SetupTraefik(); err != nil {
-			return fmt.Errorf("failed to start Traefik: %w", err)
-		}
+	// Renew the project certificate if it is about to expire
+	if err := traefik.EnsureProjectCertificate(cfg.Project, cfg.GetHostnames()); err != nil {
+		return fmt.Errorf("failed to renew project certificate: %w", err)
 	}
 
 	fmt.Printf("🚀 Starting Docker environment for: %s\n", cfg.Project)
+	warnLegacyProject(cfg.Project)
 
 	// Run docker compose up
-	dockerCmd := exec.Command("docker", "compose", "up", "-d")
-	dockerCmd.Dir = deckDir
-	dockerCmd.Stdout = os.Stdout
-	dockerCmd.Stderr = os.Stderr
-
-	if err := dockerCmd.Run(); err != nil {
+	if err := runCompose(deckDir, "up", "-d"); err != nil {
 		return fmt.Errorf("failed to start Docker containers: %w", err)
 	}
 
 	fmt.Println("\n✅ Environment started successfully!")
-	fmt.Printf("\n🌐 Your site is available at: https://%s.test\n", cfg.Project)
+	fmt.Printf("\n🌐 Your site is available at: %s\n", global.URL(cfg.GetPrimaryDomain()))
 	if cfg.GetSwoolePort() > 0 {
-		fmt.Printf("🚀 Swoole API endpoint: https://api.%s.test (port %d)\n", cfg.Project, cfg.GetSwoolePort())
+		fmt.Printf("🚀 Swoole API endpoint: %s (port %d)\n", global.URL(cfg.Subdomain("api")), cfg.GetSwoolePort())
 		fmt.Printf("   Start with: deck bin/magento swoole:server:start\n")
 	}
-	fmt.Println("\nServices:")
-	fmt.Printf("  - Web: https://%s.test\n", cfg.Project)
-	if cfg.GetSwoolePort() > 0 {
-		fmt.Printf("  - Swoole API: https://api.%s.test\n", cfg.Project)
-	}
-	fmt.Println("  - Traefik Dashboard: http://localhost:8080")
-	fmt.Printf("  - Database: %s_mariadb:3306 (user: magento, password: magento)\n", cfg.Project)
-	fmt.Printf("  - Redis: %s_redis:6379\n", cfg.Project)
-	fmt.Printf("  - OpenSearch: %s_opensearch:9200\n", cfg.Project)
-	fmt.Printf("  - RabbitMQ: %s_rabbitmq:15672 (user: guest, password: guest)\n", cfg.Project)
+	printServices(cfg, global)
 
 	return nil
 }
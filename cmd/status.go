@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/registry"
+	"github.com/caravelcommerce/deck/internal/traefik"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the containers and URLs of the project",
+	Long:  `Shows the state of each container of the project and the URLs and ports of its services.`,
+	RunE:  runStatus,
+}
+
+var statusProject string
+
+func init() {
+	statusCmd.Flags().StringVarP(&statusProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadProject(statusProject)
+	if err != nil {
+		return err
+	}
+
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Project: %s\n\n", cfg.Project)
+
+	output, err := exec.Command("docker", "ps", "-a",
+		"--filter", "label=com.docker.compose.project="+cfg.Project,
+		"--format", "{{.Names}}\t{{.Status}}").Output()
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		fmt.Println("No containers found. Run 'deck start' to start the environment.")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "CONTAINER\tSTATUS")
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if !traefik.IsTraefikRunning() {
+		fmt.Println("\n⚠️  Traefik is not running, the URLs below are unreachable. Run 'deck proxy start'.")
+	}
+
+	printServices(cfg, global)
+	return nil
+}
+
+// printServices exibe as URLs e os endereços dos serviços do projeto
+func printServices(cfg *config.DeckConfig, global *config.GlobalConfig) {
+	fmt.Println("\nServices:")
+	for _, host := range cfg.GetDomainHosts() {
+		fmt.Printf("  - Web: %s\n", global.URL(host))
+	}
+	if cfg.GetSwoolePort() > 0 {
+		fmt.Printf("  - Swoole API: %s\n", global.URL(cfg.Subdomain("api")))
+	}
+	if cfg.IsPWAEnabled() {
+		fmt.Printf("  - PWA Studio: %s\n", global.URL(cfg.Subdomain("pwa")))
+	}
+	if cfg.IsLiveReloadEnabled() {
+		fmt.Printf("  - LiveReload: %s (run 'deck watch' to rebuild styles)\n", global.URL(cfg.Subdomain("livereload")))
+	}
+	if global.DashboardEnabled() {
+		fmt.Printf("  - Traefik Dashboard: %s (user: %s, password: see %s)\n",
+			global.URL(global.Traefik.Dashboard.Host), global.Traefik.Dashboard.User, traefik.DashboardPasswordSource(global))
+	}
+
+	tcpPorts := map[string]int{}
+	if reg, err := registry.Load(); err == nil {
+		if project := reg.Get(cfg.Project); project != nil && project.Ports != nil {
+			tcpPorts = project.Ports
+		}
+	}
+
+	db := cfg.GetDatabaseCredentials()
+	fmt.Printf("  - Database: %s_mariadb:3306 (database: %s, user: %s, password: %s)\n", cfg.Project, db.Database, db.User, db.Password)
+	if port, ok := tcpPorts["mariadb"]; ok {
+		fmt.Printf("      from the host: %s:%d\n", global.Traefik.BindAddress, port)
+	}
+	switch tool := cfg.GetDatabaseTool(); tool {
+	case config.DatabaseToolAdminer:
+		fmt.Printf("      %s: %s (pre-filled login, no password needed)\n", tool, global.URL(cfg.Subdomain("db")))
+	case config.DatabaseToolPhpMyAdmin:
+		fmt.Printf("      %s: %s\n", tool, global.URL(cfg.Subdomain("db")))
+	}
+	fmt.Printf("  - Redis: %s_redis:6379\n", cfg.Project)
+	if port, ok := tcpPorts["redis"]; ok {
+		fmt.Printf("      from the host: %s:%d\n", global.Traefik.BindAddress, port)
+	}
+	if cfg.IsRedisInsightEnabled() {
+		fmt.Printf("      RedisInsight: %s\n", global.URL(cfg.Subdomain("redis")))
+	}
+	fmt.Printf("  - OpenSearch: %s_opensearch:9200\n", cfg.Project)
+	if cfg.IsOpenSearchDashboardsEnabled() {
+		fmt.Printf("  - OpenSearch Dashboards: %s\n", global.URL(cfg.Subdomain("search")))
+	}
+	rabbitmq := cfg.GetRabbitMQCredentials()
+	fmt.Printf("  - RabbitMQ Management: %s (user: %s, password: %s)\n", global.URL(cfg.Subdomain("rabbitmq")), rabbitmq.User, rabbitmq.Password)
+}
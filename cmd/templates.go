@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/caravelcommerce/deck/internal/docker"
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Manage the Docker file templates",
+	Long: `Deck renders the files in .deck from templates. Each template is looked up in
+deck/templates (or .deck.d/templates) in the project, then in the templates
+directory of the user config dir, falling back to the embedded default.`,
+}
+
+var templatesExportCmd = &cobra.Command{
+	Use:   "export [dir]",
+	Short: "Export the default templates for customization",
+	Long:  `Writes the embedded default templates to dir (deck/templates by default).`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runTemplatesExport,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates and where each one is loaded from",
+	RunE:  runTemplatesList,
+}
+
+var templatesForce bool
+
+func init() {
+	templatesExportCmd.Flags().BoolVarP(&templatesForce, "force", "f", false, "Overwrite existing template files")
+	templatesCmd.AddCommand(templatesExportCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+}
+
+func runTemplatesExport(cmd *cobra.Command, args []string) error {
+	dir := filepath.Join("deck", "templates")
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	written, err := docker.ExportTemplates(dir, templatesForce)
+	if err != nil {
+		return err
+	}
+
+	if len(written) == 0 {
+		fmt.Printf("All templates already exist in %s (use --force to overwrite)\n", dir)
+		return nil
+	}
+
+	for _, path := range written {
+		fmt.Printf("   + %s\n", path)
+	}
+	fmt.Printf("✅ Exported %d template(s) to %s\n", len(written), dir)
+	fmt.Println("\nEdit the files you want to customize, remove the others, then run 'deck setup'.")
+
+	return nil
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "TEMPLATE\tSOURCE")
+	for _, name := range docker.TemplateNames() {
+		_, source, err := docker.LoadTemplate(name, cwd)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%s\n", name, source)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [theme]",
+	Short: "Rebuild theme styles on change (Tailwind for Hyvä, Grunt for Luma)",
+	Long: `Runs the frontend watcher of the project in the Node.js container: the Tailwind
+watcher of the Hyvä theme or, for Luma-based themes, 'grunt watch'. When a theme is
+given (its Grunt alias, e.g. luma), the LESS sources are deployed first with
+'bin/magento dev:source-theme:deploy' in the PHP container and 'grunt less' is run
+before watching. With livereload: true in deck.yaml the browser reloads, or injects
+the new CSS, whenever the compiled files change.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatch,
+}
+
+var watchProject string
+
+func init() {
+	watchCmd.Flags().StringVarP(&watchProject, "project", "p", "", "Registered project name (defaults to the current directory)")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	projectDir, cfg, err := loadProject(watchProject)
+	if err != nil {
+		return err
+	}
+
+	if !cfg.IsLiveReloadEnabled() {
+		fmt.Println("💡 Set livereload: true in deck.yaml and run 'deck setup' to reload the browser automatically.")
+	}
+
+	if config.DetectHyva(projectDir) {
+		return runHyva(watchProject, args, "watch")
+	}
+	return runGrunt(projectDir, cfg.Project, args)
+}
+
+// gruntTheme configuração de um tema no themes.js do Grunt do Magento
+type gruntTheme struct {
+	Area   string   `json:"area"`
+	Name   string   `json:"name"`
+	Locale string   `json:"locale"`
+	Files  []string `json:"files"`
+}
+
+// gruntThemeScript imprime em JSON a configuração do tema, lida como o Gruntfile do Magento
+// a lê (dev/tools/grunt/configs/themes.js ou o arquivo apontado pelo grunt-config.json)
+const gruntThemeScript = `var themes = require('./dev/tools/grunt/tools/files-router').get('themes');
+var theme = themes[process.argv[1]];
+if (!theme) {
+    console.error('Theme "' + process.argv[1] + '" is not declared in the Grunt themes config. Available: ' + Object.keys(themes).join(', '));
+    process.exit(1);
+}
+console.log(JSON.stringify(theme));`
+
+// runGrunt executa o 'grunt watch' do Magento no container node. Com um tema, os fontes LESS
+// são publicados antes no container PHP (o 'grunt exec' chamaria o PHP, ausente na imagem do Node.js)
+func runGrunt(projectDir string, project string, args []string) error {
+	deckDir := filepath.Join(projectDir, ".deck")
+	if err := requireNodeService(deckDir); err != nil {
+		return err
+	}
+
+	for _, file := range []string{"Gruntfile.js", "package.json"} {
+		if _, err := os.Stat(filepath.Join(projectDir, file)); os.IsNotExist(err) {
+			return fmt.Errorf("%s not found. Copy Gruntfile.js.sample and package.json.sample from the Magento root to set up Grunt", file)
+		}
+	}
+
+	if len(args) == 1 && !phpRunning(project) {
+		return fmt.Errorf("PHP container is not running. Please run 'deck start' first")
+	}
+
+	// Dependencies are installed on the first run only
+	if err := runCompose(deckDir, "run", "--rm", "node", "sh", "-c", "[ -d node_modules ] || npm install"); err != nil {
+		return err
+	}
+
+	tasks := []string{"watch"}
+	if len(args) == 1 {
+		theme, err := gruntThemeConfig(deckDir, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("📦 Deploying LESS sources of %s...\n", theme.Name)
+		deploy := append([]string{"php", "bin/magento", "dev:source-theme:deploy"}, theme.Files...)
+		deploy = append(deploy, "--type=less", "--locale="+theme.Locale, "--area="+theme.Area, "--theme="+theme.Name)
+		if err := execInPHP(project, deploy...); err != nil {
+			return fmt.Errorf("failed to deploy theme sources: %w", err)
+		}
+
+		tasks = []string{"less:" + args[0], "watch"}
+	}
+
+	fmt.Printf("🎨 Running 'grunt %s'...\n", strings.Join(tasks, " "))
+	return runCompose(deckDir, append([]string{"run", "--rm", "node", "npx", "grunt"}, tasks...)...)
+}
+
+// gruntThemeConfig lê, no container node, a configuração do tema informado no Grunt do Magento
+func gruntThemeConfig(deckDir, name string) (*gruntTheme, error) {
+	dockerCmd := exec.Command("docker", "compose", "run", "--rm", "-T", "node", "node", "-e", gruntThemeScript, name)
+	dockerCmd.Dir = deckDir
+	dockerCmd.Stderr = os.Stderr
+	output, err := dockerCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Grunt config of theme %s: %w", name, err)
+	}
+
+	var theme gruntTheme
+	if err := json.Unmarshal(output, &theme); err != nil {
+		return nil, fmt.Errorf("failed to parse Grunt config of theme %s: %w", name, err)
+	}
+	return &theme, nil
+}
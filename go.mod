@@ -3,8 +3,9 @@ module github.com/caravelcommerce/deck
 go 1.21
 
 require (
-	gopkg.in/yaml.v3 v3.0.1
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/crypto v0.31.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
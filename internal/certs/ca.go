@@ -0,0 +1,336 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caravelcommerce/deck/internal/config"
+)
+
+const (
+	// CAName é o nome com que a CA local aparece nos trust stores
+	CAName = "Deck Local CA"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 825 * 24 * time.Hour // limite aceito pelo macOS/iOS para certificados TLS
+
+	// RenewBefore é a antecedência com que certificados são renovados antes de expirar
+	RenewBefore = 30 * 24 * time.Hour
+)
+
+// CA é a autoridade certificadora local usada para emitir os certificados dos projetos
+type CA struct {
+	Cert     *x509.Certificate
+	Key      crypto.Signer
+	CertPath string
+	KeyPath  string
+	KeyType  string // tipo de chave dos certificados emitidos (config.KeyTypeECDSA ou config.KeyTypeRSA)
+}
+
+// Dir retorna o diretório onde a CA local é armazenada (ex: ~/.config/deck/ca)
+func Dir() (string, error) {
+	dir, err := config.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ca"), nil
+}
+
+// caPaths retorna os caminhos do certificado e da chave da CA
+func caPaths() (string, string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem"), nil
+}
+
+// CACertPath retorna o caminho do certificado da CA local
+func CACertPath() (string, error) {
+	certPath, _, err := caPaths()
+	return certPath, err
+}
+
+// LoadCA carrega a CA local existente
+func LoadCA() (*CA, error) {
+	certPath, keyPath, err := caPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := ReadCertificate(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %w", err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode CA key %s", keyPath)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported CA key type %T", key)
+	}
+
+	return &CA{Cert: cert, Key: signer, CertPath: certPath, KeyPath: keyPath, KeyType: config.KeyTypeECDSA}, nil
+}
+
+// LoadOrCreateCA carrega a CA local, criando-a na primeira execução ou quando está perto de expirar.
+// keyType define o tipo de chave de uma nova CA e dos certificados emitidos por ela.
+func LoadOrCreateCA(keyType string) (*CA, bool, error) {
+	certPath, _, err := caPaths()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, err := os.Stat(certPath); err == nil {
+		ca, err := LoadCA()
+		if err != nil {
+			return nil, false, err
+		}
+		if time.Until(ca.Cert.NotAfter) > RenewBefore {
+			ca.KeyType = keyType
+			return ca, false, nil
+		}
+	}
+
+	ca, err := createCA(keyType)
+	if err != nil {
+		return nil, false, err
+	}
+	return ca, true, nil
+}
+
+// createCA gera uma nova CA raiz e a grava no diretório da CA
+func createCA(keyType string) (*CA, error) {
+	certPath, keyPath, err := caPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := generateKey(keyType, 3072)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         CAName,
+			Organization:       []string{"Deck Local Development"},
+			OrganizationalUnit: []string{hostname},
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(certPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create CA directory: %w", err)
+	}
+	if err := writeKey(keyPath, key); err != nil {
+		return nil, err
+	}
+	if err := writeCertificate(certPath, der); err != nil {
+		return nil, err
+	}
+
+	return &CA{Cert: cert, Key: key, CertPath: certPath, KeyPath: keyPath, KeyType: keyType}, nil
+}
+
+// Issue emite um certificado de servidor para os hostnames informados, gravando-o em certPath e keyPath
+func (ca *CA) Issue(hosts []string, certPath, keyPath string) error {
+	if len(hosts) == 0 {
+		return fmt.Errorf("no hostnames to issue a certificate for")
+	}
+
+	key, err := generateKey(ca.KeyType, 2048)
+	if err != nil {
+		return fmt.Errorf("failed to generate certificate key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(leafValidity)
+	if notAfter.After(ca.Cert.NotAfter) {
+		notAfter = ca.Cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   hosts[0],
+			Organization: []string{"Deck Local Development"},
+		},
+		DNSNames:    hosts,
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ca.KeyType == config.KeyTypeRSA {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate: %w", err)
+	}
+
+	if err := writeKey(keyPath, key); err != nil {
+		return err
+	}
+	// A cadeia inclui a CA para clientes que não a tenham instalada como intermediária
+	chain := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Cert.Raw})...)
+	if err := os.WriteFile(certPath, chain, 0644); err != nil {
+		return fmt.Errorf("failed to write certificate %s: %w", certPath, err)
+	}
+
+	return nil
+}
+
+// NeedsRenewal verifica se o certificado em certPath precisa ser (re)emitido: quando não existe,
+// não cobre exatamente os hostnames, não foi assinado pela CA atual, usa outro tipo de chave
+// ou está perto de expirar
+func (ca *CA) NeedsRenewal(certPath string, hosts []string) bool {
+	cert, err := ReadCertificate(certPath)
+	if err != nil {
+		return true
+	}
+
+	if cert.CheckSignatureFrom(ca.Cert) != nil {
+		return true
+	}
+
+	if time.Until(cert.NotAfter) < RenewBefore {
+		return true
+	}
+
+	if cert.PublicKeyAlgorithm != publicKeyAlgorithm(ca.KeyType) {
+		return true
+	}
+
+	names := append([]string(nil), cert.DNSNames...)
+	expected := append([]string(nil), hosts...)
+	sort.Strings(names)
+	sort.Strings(expected)
+	return strings.Join(names, ",") != strings.Join(expected, ",")
+}
+
+// Fingerprint retorna o SHA-256 do certificado em hexadecimal
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// ReadCertificate lê o primeiro certificado de um arquivo PEM
+func ReadCertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("failed to decode certificate %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s: %w", path, err)
+	}
+	return cert, nil
+}
+
+func writeCertificate(path string, der []byte) error {
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write certificate %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeKey(path string, key crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode private key: %w", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write private key %s: %w", path, err)
+	}
+	return nil
+}
+
+// generateKey gera uma chave do tipo informado; bits é o tamanho usado para chaves RSA
+func generateKey(keyType string, bits int) (crypto.Signer, error) {
+	switch keyType {
+	case config.KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, bits)
+	case config.KeyTypeECDSA, "":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", keyType)
+	}
+}
+
+// publicKeyAlgorithm retorna o algoritmo x509 correspondente ao tipo de chave
+func publicKeyAlgorithm(keyType string) x509.PublicKeyAlgorithm {
+	if keyType == config.KeyTypeRSA {
+		return x509.RSA
+	}
+	return x509.ECDSA
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
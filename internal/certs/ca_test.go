@@ -0,0 +1,248 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/caravelcommerce/deck/internal/config"
+)
+
+// newTestCA cria uma CA isolada num diretório temporário
+func newTestCA(t *testing.T, keyType string) *CA {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	ca, created, err := LoadOrCreateCA(keyType)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA(%q): %v", keyType, err)
+	}
+	if !created {
+		t.Fatalf("LoadOrCreateCA(%q) reused an existing CA in an empty directory", keyType)
+	}
+	return ca
+}
+
+// issueTestLeaf emite um certificado para hosts e devolve o certificado lido do PEM gravado
+func issueTestLeaf(t *testing.T, ca *CA, hosts []string) (*x509.Certificate, string) {
+	t.Helper()
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	if err := ca.Issue(hosts, certPath, keyPath); err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	leaf, err := ReadCertificate(certPath)
+	if err != nil {
+		t.Fatalf("ReadCertificate: %v", err)
+	}
+	return leaf, certPath
+}
+
+func TestCreateCA(t *testing.T) {
+	ca := newTestCA(t, config.KeyTypeECDSA)
+
+	cert, err := ReadCertificate(ca.CertPath)
+	if err != nil {
+		t.Fatalf("ReadCertificate: %v", err)
+	}
+	if !cert.IsCA || !cert.BasicConstraintsValid {
+		t.Error("CA certificate is not marked as a CA")
+	}
+	if cert.Subject.CommonName != CAName {
+		t.Errorf("CommonName = %q, want %q", cert.Subject.CommonName, CAName)
+	}
+	if cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		t.Error("CA certificate cannot sign certificates")
+	}
+	if got := cert.NotAfter.Sub(cert.NotBefore); got < caValidity {
+		t.Errorf("CA validity = %v, want at least %v", got, caValidity)
+	}
+
+	info, err := os.Stat(ca.KeyPath)
+	if err != nil {
+		t.Fatalf("stat CA key: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("CA key permissions = %o, want 600", perm)
+	}
+
+	loaded, created, err := LoadOrCreateCA(config.KeyTypeECDSA)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA: %v", err)
+	}
+	if created {
+		t.Error("LoadOrCreateCA recreated a valid CA")
+	}
+	if !loaded.Cert.Equal(ca.Cert) {
+		t.Error("LoadOrCreateCA returned a different CA")
+	}
+}
+
+func TestIssue(t *testing.T) {
+	tests := []struct {
+		keyType   string
+		algorithm x509.PublicKeyAlgorithm
+	}{
+		{config.KeyTypeECDSA, x509.ECDSA},
+		{config.KeyTypeRSA, x509.RSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType, func(t *testing.T) {
+			ca := newTestCA(t, tt.keyType)
+			hosts := []string{"shop.test", "*.shop.test", "loja.test"}
+
+			before := time.Now()
+			leaf, certPath := issueTestLeaf(t, ca, hosts)
+
+			if !reflect.DeepEqual(leaf.DNSNames, hosts) {
+				t.Errorf("DNSNames = %v, want %v", leaf.DNSNames, hosts)
+			}
+			if leaf.Subject.CommonName != hosts[0] {
+				t.Errorf("CommonName = %q, want %q", leaf.Subject.CommonName, hosts[0])
+			}
+			if err := leaf.CheckSignatureFrom(ca.Cert); err != nil {
+				t.Errorf("CheckSignatureFrom: %v", err)
+			}
+			if leaf.PublicKeyAlgorithm != tt.algorithm {
+				t.Errorf("PublicKeyAlgorithm = %v, want %v", leaf.PublicKeyAlgorithm, tt.algorithm)
+			}
+			if leaf.IsCA {
+				t.Error("leaf certificate is marked as a CA")
+			}
+
+			if leaf.NotBefore.After(before) {
+				t.Errorf("NotBefore = %v, want before %v", leaf.NotBefore, before)
+			}
+			if got := leaf.NotAfter.Sub(leaf.NotBefore); got > leafValidity+time.Hour {
+				t.Errorf("leaf validity = %v, exceeds the 825-day limit", got)
+			}
+			if days := time.Until(leaf.NotAfter).Hours() / 24; days > 825 || days < 824 {
+				t.Errorf("leaf expires in %.1f days, want 825", days)
+			}
+
+			if ca.NeedsRenewal(certPath, hosts) {
+				t.Error("NeedsRenewal = true for a freshly issued certificate")
+			}
+		})
+	}
+}
+
+func TestIssueCappedByCA(t *testing.T) {
+	ca := newTestCA(t, config.KeyTypeECDSA)
+	ca.Cert.NotAfter = time.Now().Add(100 * 24 * time.Hour)
+
+	leaf, _ := issueTestLeaf(t, ca, []string{"shop.test"})
+	if leaf.NotAfter.After(ca.Cert.NotAfter) {
+		t.Errorf("NotAfter = %v, outlives the CA (%v)", leaf.NotAfter, ca.Cert.NotAfter)
+	}
+}
+
+func TestIssueWithoutHosts(t *testing.T) {
+	ca := newTestCA(t, config.KeyTypeECDSA)
+	dir := t.TempDir()
+
+	if err := ca.Issue(nil, filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")); err == nil {
+		t.Error("Issue without hostnames succeeded")
+	}
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	ca := newTestCA(t, config.KeyTypeECDSA)
+	hosts := []string{"shop.test", "*.shop.test"}
+	_, certPath := issueTestLeaf(t, ca, hosts)
+
+	t.Run("missing", func(t *testing.T) {
+		if !ca.NeedsRenewal(filepath.Join(t.TempDir(), "missing.pem"), hosts) {
+			t.Error("NeedsRenewal = false for a missing certificate")
+		}
+	})
+
+	t.Run("same hosts in another order", func(t *testing.T) {
+		if ca.NeedsRenewal(certPath, []string{"*.shop.test", "shop.test"}) {
+			t.Error("NeedsRenewal = true when only the host order changed")
+		}
+	})
+
+	t.Run("host added", func(t *testing.T) {
+		if !ca.NeedsRenewal(certPath, append(hosts, "loja.test")) {
+			t.Error("NeedsRenewal = false after a host was added")
+		}
+	})
+
+	t.Run("host removed", func(t *testing.T) {
+		if !ca.NeedsRenewal(certPath, hosts[:1]) {
+			t.Error("NeedsRenewal = false after a host was removed")
+		}
+	})
+
+	t.Run("key type changed", func(t *testing.T) {
+		rsaCA := *ca
+		rsaCA.KeyType = config.KeyTypeRSA
+		if !rsaCA.NeedsRenewal(certPath, hosts) {
+			t.Error("NeedsRenewal = false after the key type changed")
+		}
+	})
+
+	t.Run("near expiry", func(t *testing.T) {
+		path := writeSignedLeaf(t, ca, hosts, time.Now().Add(RenewBefore-24*time.Hour))
+		if !ca.NeedsRenewal(path, hosts) {
+			t.Error("NeedsRenewal = false for a certificate about to expire")
+		}
+	})
+
+	t.Run("outside renewal window", func(t *testing.T) {
+		path := writeSignedLeaf(t, ca, hosts, time.Now().Add(RenewBefore+24*time.Hour))
+		if ca.NeedsRenewal(path, hosts) {
+			t.Error("NeedsRenewal = true for a certificate outside the renewal window")
+		}
+	})
+
+	t.Run("signed by another CA", func(t *testing.T) {
+		other := newTestCA(t, config.KeyTypeECDSA)
+		if !other.NeedsRenewal(certPath, hosts) {
+			t.Error("NeedsRenewal = false for a certificate signed by another CA")
+		}
+	})
+}
+
+// writeSignedLeaf grava um certificado assinado pela CA com a data de expiração informada
+func writeSignedLeaf(t *testing.T, ca *CA, hosts []string, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := generateKey(ca.KeyType, 2048)
+	if err != nil {
+		t.Fatalf("generateKey: %v", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		t.Fatalf("randomSerial: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hosts[0]},
+		DNSNames:     hosts,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	return path
+}
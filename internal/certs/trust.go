@@ -0,0 +1,230 @@
+package certs
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// Store é um trust store onde a CA local pode ser instalada
+type Store interface {
+	Name() string
+	Installed(ca *CA) bool
+	Install(ca *CA) error
+	Uninstall(ca *CA) error
+}
+
+// Stores retorna os trust stores disponíveis nesta máquina
+func Stores() []Store {
+	var stores []Store
+
+	switch runtime.GOOS {
+	case "darwin":
+		stores = append(stores, macStore{})
+	case "linux":
+		if store, ok := findLinuxStore(); ok {
+			stores = append(stores, store)
+		}
+	}
+
+	if _, err := exec.LookPath("certutil"); err == nil {
+		for _, db := range nssDatabases() {
+			stores = append(stores, db)
+		}
+	}
+
+	return stores
+}
+
+// linuxStore é o trust store do sistema em distribuições Linux
+type linuxStore struct {
+	dir    string
+	file   string
+	update []string
+}
+
+// linuxStores lista os diretórios de âncoras das principais distribuições
+var linuxStores = []linuxStore{
+	{dir: "/usr/local/share/ca-certificates", file: "deck-local-ca.crt", update: []string{"update-ca-certificates"}},           // Debian/Ubuntu
+	{dir: "/etc/pki/ca-trust/source/anchors", file: "deck-local-ca.pem", update: []string{"update-ca-trust", "extract"}},       // Fedora/RHEL
+	{dir: "/etc/ca-certificates/trust-source/anchors", file: "deck-local-ca.crt", update: []string{"trust", "extract-compat"}}, // Arch
+	{dir: "/usr/share/pki/trust/anchors", file: "deck-local-ca.pem", update: []string{"update-ca-certificates"}},               // openSUSE
+}
+
+func findLinuxStore() (linuxStore, bool) {
+	for _, store := range linuxStores {
+		if !isDir(store.dir) {
+			continue
+		}
+		if _, err := exec.LookPath(store.update[0]); err != nil {
+			continue
+		}
+		return store, true
+	}
+	return linuxStore{}, false
+}
+
+func (s linuxStore) Name() string {
+	return "system (" + s.dir + ")"
+}
+
+func (s linuxStore) path() string {
+	return filepath.Join(s.dir, s.file)
+}
+
+func (s linuxStore) Installed(ca *CA) bool {
+	cert, err := ReadCertificate(s.path())
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(cert.Raw, ca.Cert.Raw)
+}
+
+func (s linuxStore) Install(ca *CA) error {
+	if err := runPrivileged("cp", ca.CertPath, s.path()); err != nil {
+		return fmt.Errorf("failed to copy CA certificate to %s: %w", s.dir, err)
+	}
+	if err := runPrivileged(s.update...); err != nil {
+		return fmt.Errorf("failed to update system trust store: %w", err)
+	}
+	return nil
+}
+
+func (s linuxStore) Uninstall(ca *CA) error {
+	if _, err := os.Stat(s.path()); os.IsNotExist(err) {
+		return nil
+	}
+	if err := runPrivileged("rm", "-f", s.path()); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", s.path(), err)
+	}
+	if err := runPrivileged(s.update...); err != nil {
+		return fmt.Errorf("failed to update system trust store: %w", err)
+	}
+	return nil
+}
+
+// macStore é o keychain do sistema no macOS
+type macStore struct{}
+
+const macKeychain = "/Library/Keychains/System.keychain"
+
+func (macStore) Name() string {
+	return "system keychain"
+}
+
+func (macStore) Installed(ca *CA) bool {
+	return exec.Command("security", "verify-cert", "-c", ca.CertPath).Run() == nil
+}
+
+func (macStore) Install(ca *CA) error {
+	if err := runPrivileged("security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", macKeychain, ca.CertPath); err != nil {
+		return fmt.Errorf("failed to add CA to the system keychain: %w", err)
+	}
+	return nil
+}
+
+func (macStore) Uninstall(ca *CA) error {
+	if err := runPrivileged("security", "delete-certificate", "-c", CAName, macKeychain); err != nil {
+		return fmt.Errorf("failed to remove CA from the system keychain: %w", err)
+	}
+	return nil
+}
+
+// nssDatabase é um banco de certificados NSS (Firefox, Chrome/Chromium no Linux)
+type nssDatabase struct {
+	dir string
+	db  string
+}
+
+// nssDatabases encontra os bancos NSS do usuário
+func nssDatabases() []nssDatabase {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	candidates := []string{
+		filepath.Join(homeDir, ".pki", "nssdb"),
+		filepath.Join(homeDir, "snap", "chromium", "current", ".pki", "nssdb"),
+	}
+	for _, pattern := range []string{
+		filepath.Join(homeDir, ".mozilla", "firefox", "*"),
+		filepath.Join(homeDir, "snap", "firefox", "common", ".mozilla", "firefox", "*"),
+		filepath.Join(homeDir, "Library", "Application Support", "Firefox", "Profiles", "*"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		candidates = append(candidates, matches...)
+	}
+
+	var databases []nssDatabase
+	for _, dir := range candidates {
+		switch {
+		case fileExists(filepath.Join(dir, "cert9.db")):
+			databases = append(databases, nssDatabase{dir: dir, db: "sql:" + dir})
+		case fileExists(filepath.Join(dir, "cert8.db")):
+			databases = append(databases, nssDatabase{dir: dir, db: "dbm:" + dir})
+		}
+	}
+	return databases
+}
+
+func (d nssDatabase) Name() string {
+	return "nss (" + d.dir + ")"
+}
+
+func (d nssDatabase) Installed(ca *CA) bool {
+	out, err := exec.Command("certutil", "-L", "-d", d.db, "-n", CAName, "-a").Output()
+	if err != nil {
+		return false
+	}
+	block, _ := pem.Decode(bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n")))
+	return block != nil && bytes.Equal(block.Bytes, ca.Cert.Raw)
+}
+
+func (d nssDatabase) Install(ca *CA) error {
+	// Remove uma CA anterior com o mesmo nome antes de adicionar a atual
+	_ = exec.Command("certutil", "-D", "-d", d.db, "-n", CAName).Run()
+
+	out, err := exec.Command("certutil", "-A", "-d", d.db, "-t", "C,,", "-n", CAName, "-i", ca.CertPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to add CA to %s: %s", d.dir, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+func (d nssDatabase) Uninstall(ca *CA) error {
+	if !d.Installed(ca) {
+		return nil
+	}
+	out, err := exec.Command("certutil", "-D", "-d", d.db, "-n", CAName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to remove CA from %s: %s", d.dir, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// runPrivileged executa um comando como root, usando sudo quando necessário
+func runPrivileged(args ...string) error {
+	if os.Geteuid() != 0 {
+		args = append([]string{"sudo"}, args...)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
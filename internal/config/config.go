@@ -13,8 +13,9 @@ import (
 
 // DeckConfig estrutura principal de configuração
 type DeckConfig struct {
-	Project    string            `yaml:"project"`  // Nome do projeto
-	Magento    string            `yaml:"magento"`  // Versão do Magento
+	Project    string            `yaml:"project"` // Nome do projeto
+	Magento    string            `yaml:"magento"` // Versão do Magento
+	Mode       string            `yaml:"mode,omitempty"`
 	PHP        *PHPConfig        `yaml:"php,omitempty"`
 	Nginx      *NginxConfig      `yaml:"nginx,omitempty"`
 	MariaDB    *MariaDBConfig    `yaml:"mariadb,omitempty"`
@@ -23,24 +24,44 @@ type DeckConfig struct {
 	RabbitMQ   *RabbitMQConfig   `yaml:"rabbitmq,omitempty"`
 	Node       *NodeConfig       `yaml:"node,omitempty"`
 	Swoole     *SwooleConfig     `yaml:"swoole,omitempty"`
+	Domains    []DomainConfig    `yaml:"domains,omitempty"`
+	Tools      *ToolsConfig      `yaml:"tools,omitempty"`
+	PWA        *PWAConfig        `yaml:"pwa,omitempty"`
+	LiveReload bool              `yaml:"livereload,omitempty"`
+
+	sources map[string]string // origem de cada valor resolvido (ex: "php.version")
 }
 
-// LoadConfig carrega e processa a configuração
+// LoadConfig carrega e processa a configuração: deck.yaml, com variáveis de ambiente
+// interpoladas, mesclado com o deck.local.yaml opcional
 func LoadConfig(path string) (*DeckConfig, error) {
-	data, err := os.ReadFile(path)
+	lookup := envLookup(filepath.Dir(path))
+
+	base, err := loadLayer(path, lookup, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read deck.yaml: %w", err)
+		return nil, err
 	}
+	sources := base.sources()
 
-	var config DeckConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse deck.yaml: %w", err)
+	localPath := filepath.Join(filepath.Dir(path), LocalConfigFile)
+	if _, err := os.Stat(localPath); err == nil {
+		local, err := loadLayer(localPath, lookup, true)
+		if err != nil {
+			return nil, err
+		}
+		if local.root != nil {
+			for valuePath, source := range local.sources() {
+				sources[valuePath] = source
+			}
+			mergeNodes(base.root, local.root)
+		}
 	}
 
-	// Validate required fields
-	if config.Project == "" {
-		return nil, fmt.Errorf("project name is required in deck.yaml")
+	var config DeckConfig
+	if err := base.root.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse deck.yaml: %w", err)
 	}
+	config.sources = sources
 
 	// Apply Magento version defaults if specified
 	if config.Magento != "" {
@@ -61,6 +82,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	if err != nil {
 		return fmt.Errorf("failed to get Magento requirements: %w", err)
 	}
+	source := magentoSource(c.Magento)
 
 	// PHP
 	if c.PHP == nil {
@@ -68,6 +90,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	}
 	if c.PHP.Version == "" {
 		c.PHP.Version = requirements.PHP
+		c.setSource("php.version", source)
 	}
 
 	// Nginx
@@ -76,6 +99,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	}
 	if c.Nginx.Version == "" {
 		c.Nginx.Version = requirements.Nginx
+		c.setSource("nginx.version", source)
 	}
 
 	// MariaDB
@@ -84,6 +108,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	}
 	if c.MariaDB.Version == "" {
 		c.MariaDB.Version = requirements.MariaDB
+		c.setSource("mariadb.version", source)
 	}
 
 	// OpenSearch
@@ -92,6 +117,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	}
 	if c.OpenSearch.Version == "" {
 		c.OpenSearch.Version = requirements.OpenSearch
+		c.setSource("opensearch.version", source)
 	}
 
 	// Redis
@@ -100,6 +126,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	}
 	if c.Redis.Version == "" {
 		c.Redis.Version = requirements.Redis
+		c.setSource("redis.version", source)
 	}
 
 	// RabbitMQ
@@ -108,6 +135,7 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 	}
 	if c.RabbitMQ.Version == "" {
 		c.RabbitMQ.Version = requirements.RabbitMQ
+		c.setSource("rabbitmq.version", source)
 	}
 
 	return nil
@@ -115,12 +143,15 @@ func (c *DeckConfig) applyMagentoDefaults() error {
 
 // applyDefaults aplica defaults finais
 func (c *DeckConfig) applyDefaults() {
+	c.setDefault(&c.Mode, "mode", MageModeDeveloper)
+
 	// PHP defaults
 	if c.PHP == nil {
-		c.PHP = &PHPConfig{Version: "8.3"}
+		c.PHP = &PHPConfig{}
 	}
 	if c.PHP.Version == "" {
 		c.PHP.Version = "8.3"
+		c.setSource("php.version", SourceDefault)
 	}
 	// Extensões padrão do Magento
 	if c.PHP.Extensions == nil || len(c.PHP.Extensions) == 0 {
@@ -128,51 +159,108 @@ func (c *DeckConfig) applyDefaults() {
 			"bcmath", "gd", "intl", "mbstring", "pdo_mysql",
 			"soap", "sockets", "xsl", "zip", "opcache",
 		}
+		c.setSource("php.extensions", SourceDefault)
+	}
+	// Em produção o PHP não verifica alterações nos arquivos, a menos que o perfil seja definido
+	if c.Mode == MageModeProduction {
+		c.setDefault(&c.PHP.Profile, "php.profile", PHPProfilePerformance)
 	}
+	c.setDefault(&c.PHP.Profile, "php.profile", PHPProfileDeveloper)
 
 	// Nginx defaults
 	if c.Nginx == nil {
-		c.Nginx = &NginxConfig{Version: "1.28"}
+		c.Nginx = &NginxConfig{}
 	}
 	if c.Nginx.Version == "" {
 		c.Nginx.Version = "1.28"
+		c.setSource("nginx.version", SourceDefault)
 	}
 
 	// MariaDB defaults
 	if c.MariaDB == nil {
-		c.MariaDB = &MariaDBConfig{Version: "11.4"}
+		c.MariaDB = &MariaDBConfig{}
 	}
 	if c.MariaDB.Version == "" {
 		c.MariaDB.Version = "11.4"
+		c.setSource("mariadb.version", SourceDefault)
 	}
 
 	// OpenSearch defaults
 	if c.OpenSearch == nil {
-		c.OpenSearch = &OpenSearchConfig{Version: "3"}
+		c.OpenSearch = &OpenSearchConfig{}
 	}
 	if c.OpenSearch.Version == "" {
 		c.OpenSearch.Version = "3"
+		c.setSource("opensearch.version", SourceDefault)
 	}
 
 	// Redis defaults
 	if c.Redis == nil {
-		c.Redis = &RedisConfig{Version: "7.4"}
+		c.Redis = &RedisConfig{}
 	}
 	if c.Redis.Version == "" {
 		c.Redis.Version = "7.4"
+		c.setSource("redis.version", SourceDefault)
 	}
 
 	// RabbitMQ defaults
 	if c.RabbitMQ == nil {
-		c.RabbitMQ = &RabbitMQConfig{Version: "4.1"}
+		c.RabbitMQ = &RabbitMQConfig{}
 	}
 	if c.RabbitMQ.Version == "" {
 		c.RabbitMQ.Version = "4.1"
+		c.setSource("rabbitmq.version", SourceDefault)
+	}
+
+	// Credenciais padrão
+	if c.MariaDB.Credentials == nil {
+		c.MariaDB.Credentials = &DatabaseCredentials{}
+	}
+	c.setDefault(&c.MariaDB.Credentials.RootPassword, "mariadb.credentials.root_password", "root")
+	c.setDefault(&c.MariaDB.Credentials.Database, "mariadb.credentials.database", "magento")
+	c.setDefault(&c.MariaDB.Credentials.User, "mariadb.credentials.user", "magento")
+	c.setDefault(&c.MariaDB.Credentials.Password, "mariadb.credentials.password", "magento")
+
+	if c.RabbitMQ.Credentials == nil {
+		c.RabbitMQ.Credentials = &RabbitMQCredentials{}
+	}
+	c.setDefault(&c.RabbitMQ.Credentials.User, "rabbitmq.credentials.user", "guest")
+	c.setDefault(&c.RabbitMQ.Credentials.Password, "rabbitmq.credentials.password", "guest")
+
+	// Domínio padrão: {project}.test
+	if len(c.Domains) == 0 {
+		c.Domains = []DomainConfig{{Host: c.Project + ".test"}}
+		c.setSource("domains", SourceDefault)
+	}
+	for i := range c.Domains {
+		if c.Domains[i].RunCode != "" && c.Domains[i].RunType == "" {
+			c.Domains[i].RunType = "website"
+		}
 	}
 
 	// Swoole defaults
 	if c.Swoole != nil && c.Swoole.Enabled && c.Swoole.Port == 0 {
 		c.Swoole.Port = 9501
+		c.setSource("swoole.port", SourceDefault)
+	}
+
+	// PWA Studio defaults
+	if c.PWA != nil {
+		c.setDefault(&c.PWA.Path, "pwa.path", "pwa-studio")
+		if c.PWA.Port == 0 {
+			c.PWA.Port = 8080
+			c.setSource("pwa.port", SourceDefault)
+		}
+		c.setDefault(&c.PWA.Command, "pwa.command", "yarn watch")
+		c.setDefault(&c.PWA.BackendURL, "pwa.backend_url", "http://"+c.Project+"_nginx/")
+	}
+}
+
+// setDefault preenche um valor vazio com o default, registrando a origem
+func (c *DeckConfig) setDefault(field *string, path, value string) {
+	if *field == "" {
+		*field = value
+		c.setSource(path, SourceDefault)
 	}
 }
 
@@ -191,6 +279,38 @@ func (c *DeckConfig) HasPHPExtension(ext string) bool {
 	return c.PHP.HasExtension(ext)
 }
 
+// GetMode retorna o modo do Magento
+func (c *DeckConfig) GetMode() string {
+	if c.Mode == "" {
+		return MageModeDeveloper
+	}
+	return c.Mode
+}
+
+// GetPHPProfile retorna o perfil de runtime do PHP
+func (c *DeckConfig) GetPHPProfile() string {
+	if c.PHP == nil || c.PHP.Profile == "" {
+		return PHPProfileDeveloper
+	}
+	return c.PHP.Profile
+}
+
+// GetPHPIniSettings retorna as diretivas do php.ini definidas no deck.yaml, ordenadas pelo nome
+func (c *DeckConfig) GetPHPIniSettings() []Setting {
+	if c.PHP == nil {
+		return nil
+	}
+	return sortedSettings(c.PHP.INI)
+}
+
+// GetPHPFPMSettings retorna as diretivas do pool do PHP-FPM definidas no deck.yaml, ordenadas pelo nome
+func (c *DeckConfig) GetPHPFPMSettings() []Setting {
+	if c.PHP == nil {
+		return nil
+	}
+	return sortedSettings(c.PHP.FPM)
+}
+
 func (c *DeckConfig) GetNodeVersion() string {
 	return c.Node.GetVersion()
 }
@@ -199,6 +319,38 @@ func (c *DeckConfig) IsNodeEnabled() bool {
 	return c.Node != nil && c.Node.Version != ""
 }
 
+// GetNodeImageVersion retorna a versão do Node.js configurada ou a padrão
+func (c *DeckConfig) GetNodeImageVersion() string {
+	if c.IsNodeEnabled() {
+		return c.GetNodeVersion()
+	}
+	return DefaultNodeVersion
+}
+
+func (c *DeckConfig) IsPWAEnabled() bool {
+	return c.PWA != nil
+}
+
+func (c *DeckConfig) IsLiveReloadEnabled() bool {
+	return c.LiveReload
+}
+
+func (c *DeckConfig) IsOpenSearchDashboardsEnabled() bool {
+	return c.OpenSearch != nil && c.OpenSearch.Dashboards
+}
+
+// GetDatabaseTool retorna a ferramenta web do banco de dados (adminer, phpmyadmin ou vazio)
+func (c *DeckConfig) GetDatabaseTool() string {
+	if c.Tools == nil {
+		return ""
+	}
+	return c.Tools.Database
+}
+
+func (c *DeckConfig) IsRedisInsightEnabled() bool {
+	return c.Tools != nil && c.Tools.RedisInsight
+}
+
 func (c *DeckConfig) IsSwooleEnabled() bool {
 	return c.Swoole != nil && c.Swoole.Enabled
 }
@@ -210,6 +362,89 @@ func (c *DeckConfig) GetSwoolePort() int {
 	return c.Swoole.Port
 }
 
+// GetDomainHosts retorna os hostnames da loja, o primeiro sendo o principal
+func (c *DeckConfig) GetDomainHosts() []string {
+	hosts := make([]string, 0, len(c.Domains))
+	for _, domain := range c.Domains {
+		hosts = append(hosts, domain.Host)
+	}
+	return hosts
+}
+
+// GetPrimaryDomain retorna o hostname principal da loja
+func (c *DeckConfig) GetPrimaryDomain() string {
+	if len(c.Domains) == 0 {
+		return c.Project + ".test"
+	}
+	return c.Domains[0].Host
+}
+
+// HasRunCodes indica se algum domínio define MAGE_RUN_CODE
+func (c *DeckConfig) HasRunCodes() bool {
+	for _, domain := range c.Domains {
+		if domain.RunCode != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Subdomain retorna o hostname de um serviço auxiliar sob o domínio principal
+// (ex: api.{project}.test; com o domínio principal www.loja.local, api.loja.local)
+func (c *DeckConfig) Subdomain(name string) string {
+	return name + "." + strings.TrimPrefix(c.GetPrimaryDomain(), "www.")
+}
+
+// GetHostnames retorna todos os hostnames roteados pelo Traefik para o projeto
+func (c *DeckConfig) GetHostnames() []string {
+	hostnames := c.GetDomainHosts()
+	if c.GetSwoolePort() > 0 {
+		hostnames = append(hostnames, c.Subdomain("api"))
+	}
+	hostnames = append(hostnames, c.Subdomain("rabbitmq"))
+	if c.IsOpenSearchDashboardsEnabled() {
+		hostnames = append(hostnames, c.Subdomain("search"))
+	}
+	if c.IsPWAEnabled() {
+		hostnames = append(hostnames, c.Subdomain("pwa"))
+	}
+	if c.IsLiveReloadEnabled() {
+		hostnames = append(hostnames, c.Subdomain("livereload"))
+	}
+	if c.GetDatabaseTool() != "" {
+		hostnames = append(hostnames, c.Subdomain("db"))
+	}
+	if c.IsRedisInsightEnabled() {
+		hostnames = append(hostnames, c.Subdomain("redis"))
+	}
+	return hostnames
+}
+
+// TCPService serviço que pode ser exposto ao host por uma porta TCP dedicada no Traefik
+type TCPService struct {
+	Port         int // porta do serviço no container
+	HostPortBase int // primeira porta do host considerada na alocação
+}
+
+// TCPServices serviços expostos por TCP; MySQL e Redis não suportam roteamento por SNI,
+// então cada projeto recebe uma porta própria por serviço
+var TCPServices = map[string]TCPService{
+	"mariadb": {Port: 3306, HostPortBase: 33061},
+	"redis":   {Port: 6379, HostPortBase: 63791},
+}
+
+// ExposedServices retorna, em ordem alfabética, os serviços TCP que devem ser expostos ao host
+func (c *DeckConfig) ExposedServices() []string {
+	var services []string
+	if c.MariaDB == nil || c.MariaDB.Expose == nil || *c.MariaDB.Expose {
+		services = append(services, "mariadb")
+	}
+	if c.Redis == nil || c.Redis.Expose == nil || *c.Redis.Expose {
+		services = append(services, "redis")
+	}
+	return services
+}
+
 // GetPHPVersion retorna a versão do PHP
 func (c *DeckConfig) GetPHPVersion() string {
 	if c.PHP == nil {
@@ -250,6 +485,22 @@ func (c *DeckConfig) GetRedisVersion() string {
 	return c.Redis.Version
 }
 
+// GetDatabaseCredentials retorna as credenciais do MariaDB
+func (c *DeckConfig) GetDatabaseCredentials() DatabaseCredentials {
+	if c.MariaDB == nil || c.MariaDB.Credentials == nil {
+		return DatabaseCredentials{}
+	}
+	return *c.MariaDB.Credentials
+}
+
+// GetRabbitMQCredentials retorna as credenciais do RabbitMQ
+func (c *DeckConfig) GetRabbitMQCredentials() RabbitMQCredentials {
+	if c.RabbitMQ == nil || c.RabbitMQ.Credentials == nil {
+		return RabbitMQCredentials{}
+	}
+	return *c.RabbitMQ.Credentials
+}
+
 // GetRabbitMQVersion retorna a versão do RabbitMQ
 func (c *DeckConfig) GetRabbitMQVersion() string {
 	if c.RabbitMQ == nil {
@@ -272,7 +523,8 @@ func CreateDeckYAML(path, projectName, magentoVersion string) error {
 	}
 
 	// Adiciona comentário no topo
-	header := `# Deck - Magento 2 Development Environment
+	header := `# yaml-language-server: $schema=` + SchemaURL + `
+# Deck - Magento 2 Development Environment
 # Auto-generated configuration file
 
 `
@@ -294,51 +546,184 @@ func DeckYAMLExists(projectPath string) bool {
 
 // ComposerJSON estrutura para ler o composer.json
 type ComposerJSON struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version"`
 	Require map[string]string `json:"require"`
 }
 
-// DetectMagentoVersion detecta a versão do Magento a partir do composer.json
+// composerLock estrutura para ler os pacotes instalados do composer.lock
+type composerLock struct {
+	Packages    []composerPackage `json:"packages"`
+	PackagesDev []composerPackage `json:"packages-dev"`
+}
+
+type composerPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// magentoPackage pacote composer que identifica a versão e a edição do Magento
+type magentoPackage struct {
+	Name    string
+	Edition string
+	MageOS  bool // versões do Mage-OS seguem numeração própria
+}
+
+// magentoPackages pacotes procurados, em ordem de prioridade: projetos Commerce também instalam
+// o pacote Community, e os metapacotes product-* têm precedência sobre os pacotes *-base
+var magentoPackages = []magentoPackage{
+	{Name: "magento/product-enterprise-edition", Edition: "Adobe Commerce"},
+	{Name: "magento/product-community-edition", Edition: "Magento Open Source"},
+	{Name: "mage-os/product-community-edition", Edition: "Mage-OS", MageOS: true},
+	{Name: "magento/magento2-ee-base", Edition: "Adobe Commerce"},
+	{Name: "magento/magento2-base", Edition: "Magento Open Source"},
+	{Name: "mage-os/magento2-base", Edition: "Mage-OS", MageOS: true},
+}
+
+// magentoProjectNames nomes de projetos (campo name do composer.json) cuja versão é a do próprio Magento,
+// como o repositório magento2 ou instalações feitas com 'composer create-project'
+var magentoProjectNames = map[string]string{
+	"magento/project-community-edition":  "Magento Open Source",
+	"magento/project-enterprise-edition": "Adobe Commerce",
+	"magento/magento2ce":                 "Magento Open Source",
+	"magento/magento2ee":                 "Adobe Commerce",
+}
+
+// DetectedMagento versão do Magento detectada no projeto
+type DetectedMagento struct {
+	Version string // versão suportada pelo Deck (ex: 2.4.8-p3)
+	Edition string // Magento Open Source, Adobe Commerce ou Mage-OS
+	Package string // pacote composer usado na detecção
+	Found   string // versão ou restrição encontrada (ex: "~2.4.8", "1.1.0")
+	Source  string // composer.lock ou composer.json
+	Warning string // aviso quando a versão encontrada não é conhecida e outra foi usada no lugar
+}
+
+// isSupportedVersion informa se a versão exata está na lista de versões suportadas
+func isSupportedVersion(version string) bool {
+	for _, supported := range magento.GetSupportedVersions() {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectMagentoVersion detecta a versão do Magento a partir do composer.lock ou do composer.json
 func DetectMagentoVersion(projectPath string) (string, error) {
+	detected, err := DetectMagento(projectPath)
+	if err != nil {
+		return "", err
+	}
+	return detected.Version, nil
+}
+
+// DetectMagento detecta a versão e a edição do Magento. A versão instalada no composer.lock tem
+// preferência; sem ele, a restrição do composer.json é resolvida contra as versões suportadas.
+func DetectMagento(projectPath string) (*DetectedMagento, error) {
+	if detected, err := detectFromLock(projectPath); detected != nil || err != nil {
+		return detected, err
+	}
+	return detectFromComposerJSON(projectPath)
+}
+
+// detectFromLock procura a versão instalada no composer.lock; retorna nil se não houver lock ou pacote do Magento
+func detectFromLock(projectPath string) (*DetectedMagento, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "composer.lock"))
+	if err != nil {
+		return nil, nil
+	}
+
+	var lock composerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse composer.lock: %w", err)
+	}
+
+	installed := make(map[string]string)
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		installed[pkg.Name] = pkg.Version
+	}
+
+	for _, pkg := range magentoPackages {
+		version, ok := installed[pkg.Name]
+		if !ok {
+			continue
+		}
+		detected := &DetectedMagento{Edition: pkg.Edition, Package: pkg.Name, Found: version, Source: "composer.lock"}
+
+		if pkg.MageOS {
+			if detected.Version, err = magento.ResolveMageOS(version); err != nil {
+				return nil, err
+			}
+			return detected, nil
+		}
+
+		// Versões instaladas são exatas; patches mais novos que os conhecidos usam o último patch
+		// conhecido da mesma versão base
+		version = strings.TrimPrefix(version, "v")
+		if isSupportedVersion(version) {
+			detected.Version = version
+			return detected, nil
+		}
+		base, _, _ := strings.Cut(version, "-")
+		if detected.Version, err = magento.ResolveConstraint(base + "-p*"); err != nil {
+			return nil, fmt.Errorf("Magento %s (%s in composer.lock) is not supported (versions available: %s)",
+				version, pkg.Name, strings.Join(magento.GetSupportedVersions(), ", "))
+		}
+		detected.Warning = fmt.Sprintf("Magento %s is not known to this Deck version; using the requirements of %s", version, detected.Version)
+		return detected, nil
+	}
+
+	return nil, nil
+}
+
+// detectFromComposerJSON resolve a restrição de versão do Magento exigida no composer.json
+func detectFromComposerJSON(projectPath string) (*DetectedMagento, error) {
 	composerPath := filepath.Join(projectPath, "composer.json")
 
 	// Verifica se o composer.json existe
 	if _, err := os.Stat(composerPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("composer.json not found in project directory")
+		return nil, fmt.Errorf("composer.json not found in project directory")
 	}
 
 	// Lê o composer.json
 	data, err := os.ReadFile(composerPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read composer.json: %w", err)
+		return nil, fmt.Errorf("failed to read composer.json: %w", err)
 	}
 
 	// Parse do JSON
 	var composer ComposerJSON
 	if err := json.Unmarshal(data, &composer); err != nil {
-		return "", fmt.Errorf("failed to parse composer.json: %w", err)
+		return nil, fmt.Errorf("failed to parse composer.json: %w", err)
 	}
 
-	// Procura pela versão do Magento
-	// Pode estar em magento/product-community-edition ou magento/product-enterprise-edition
-	version := ""
-	if v, ok := composer.Require["magento/product-community-edition"]; ok {
-		version = v
-	} else if v, ok := composer.Require["magento/product-enterprise-edition"]; ok {
-		version = v
-	}
+	for _, pkg := range magentoPackages {
+		constraint, ok := composer.Require[pkg.Name]
+		if !ok {
+			continue
+		}
+		detected := &DetectedMagento{Edition: pkg.Edition, Package: pkg.Name, Found: constraint, Source: "composer.json"}
 
-	if version == "" {
-		return "", fmt.Errorf("Magento version not found in composer.json")
+		if pkg.MageOS {
+			detected.Version, err = magento.ResolveMageOS(constraint)
+		} else {
+			detected.Version, err = magento.ResolveConstraint(constraint)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s in composer.json: %w", pkg.Name, err)
+		}
+		return detected, nil
 	}
 
-	// Remove caracteres especiais de versão do composer (^, ~, *, etc)
-	version = strings.TrimPrefix(version, "^")
-	version = strings.TrimPrefix(version, "~")
-	version = strings.TrimPrefix(version, ">=")
-	version = strings.TrimPrefix(version, "<=")
-	version = strings.TrimPrefix(version, ">")
-	version = strings.TrimPrefix(version, "<")
-	version = strings.TrimSpace(version)
+	// O próprio projeto é o Magento (repositório magento2 ou create-project com a versão declarada)
+	if edition, ok := magentoProjectNames[composer.Name]; ok && composer.Version != "" {
+		version, err := magento.ResolveConstraint(strings.TrimSuffix(composer.Version, "-dev"))
+		if err != nil {
+			return nil, fmt.Errorf("%s in composer.json: %w", composer.Name, err)
+		}
+		return &DetectedMagento{Version: version, Edition: edition, Package: composer.Name, Found: composer.Version, Source: "composer.json"}, nil
+	}
 
-	return version, nil
+	return nil, fmt.Errorf("Magento version not found in composer.json")
 }
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeComposerFiles grava os arquivos do composer informados num diretório temporário
+func writeComposerFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestDetectMagento(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    DetectedMagento
+		warning bool
+	}{
+		{
+			name: "lock with known version",
+			files: map[string]string{
+				"composer.json": `{"require": {"magento/product-community-edition": "^2.4.7"}}`,
+				"composer.lock": `{"packages": [{"name": "magento/product-community-edition", "version": "2.4.7-p2"}]}`,
+			},
+			want: DetectedMagento{Version: "2.4.7-p2", Edition: "Magento Open Source", Package: "magento/product-community-edition", Found: "2.4.7-p2", Source: "composer.lock"},
+		},
+		{
+			name: "lock with newer patch",
+			files: map[string]string{
+				"composer.lock": `{"packages": [{"name": "magento/product-enterprise-edition", "version": "2.4.8-p9"}]}`,
+			},
+			want:    DetectedMagento{Version: "2.4.8-p3", Edition: "Adobe Commerce", Package: "magento/product-enterprise-edition", Found: "2.4.8-p9", Source: "composer.lock"},
+			warning: true,
+		},
+		{
+			name: "lock with Mage-OS",
+			files: map[string]string{
+				"composer.lock": `{"packages": [{"name": "mage-os/product-community-edition", "version": "1.1.0"}]}`,
+			},
+			want: DetectedMagento{Version: "2.4.8-p3", Edition: "Mage-OS", Package: "mage-os/product-community-edition", Found: "1.1.0", Source: "composer.lock"},
+		},
+		{
+			name: "composer.json constraint",
+			files: map[string]string{
+				"composer.json": `{"require": {"magento/product-community-edition": "~2.4.7-p1 <2.4.8"}}`,
+			},
+			want: DetectedMagento{Version: "2.4.7-p3", Edition: "Magento Open Source", Package: "magento/product-community-edition", Found: "~2.4.7-p1 <2.4.8", Source: "composer.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detected, err := DetectMagento(writeComposerFiles(t, tt.files))
+			if err != nil {
+				t.Fatalf("DetectMagento: %v", err)
+			}
+			if (detected.Warning != "") != tt.warning {
+				t.Errorf("Warning = %q, want warning: %v", detected.Warning, tt.warning)
+			}
+			detected.Warning = ""
+			if *detected != tt.want {
+				t.Errorf("DetectMagento = %+v, want %+v", *detected, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectMagentoUnsupported(t *testing.T) {
+	dir := writeComposerFiles(t, map[string]string{
+		"composer.lock": `{"packages": [{"name": "magento/product-community-edition", "version": "2.4.6-p3"}]}`,
+	})
+	if detected, err := DetectMagento(dir); err == nil {
+		t.Errorf("DetectMagento = %+v, want an error for an unsupported version", *detected)
+	}
+}
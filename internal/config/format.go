@@ -0,0 +1,233 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// deckConfigAlias evita a recursão de DeckConfig.UnmarshalYAML
+type deckConfigAlias DeckConfig
+
+// deckConfigYAML formato aceito no deck.yaml: a forma canônica mais os atalhos documentados no README
+type deckConfigYAML struct {
+	deckConfigAlias `yaml:",inline"`
+
+	Name       string `yaml:"name,omitempty"`        // atalho para project
+	OpenSwoole *bool  `yaml:"openswoole,omitempty"`  // atalho para swoole.enabled
+	SwoolePort int    `yaml:"swoole_port,omitempty"` // atalho para swoole.port
+}
+
+// UnmarshalYAML aceita tanto o formato canônico quanto o formato plano do README
+// (name, openswoole e swoole_port no primeiro nível)
+func (c *DeckConfig) UnmarshalYAML(node *yaml.Node) error {
+	var raw deckConfigYAML
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	*c = DeckConfig(raw.deckConfigAlias)
+
+	if c.Project == "" {
+		c.Project = raw.Name
+	}
+	if raw.OpenSwoole != nil || raw.SwoolePort != 0 {
+		if c.Swoole == nil {
+			c.Swoole = &SwooleConfig{}
+		}
+		if raw.OpenSwoole != nil {
+			c.Swoole.Enabled = *raw.OpenSwoole
+		}
+		if raw.SwoolePort != 0 {
+			c.Swoole.Port = raw.SwoolePort
+		}
+	}
+
+	return nil
+}
+
+// versionShorthandKeys serviços que aceitam a forma abreviada "serviço: versão"
+var versionShorthandKeys = []string{"php", "nginx", "mariadb", "opensearch", "redis", "rabbitmq", "node"}
+
+// Migrate reescreve um deck.yaml no formato canônico, preservando comentários.
+// Retorna false se o arquivo já estiver no formato canônico.
+func Migrate(data []byte) ([]byte, bool, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to parse deck.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 || !canonicalize(doc.Content[0]) {
+		return data, false, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, false, fmt.Errorf("failed to encode deck.yaml: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to encode deck.yaml: %w", err)
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// SetValue define uma chave de primeiro nível em um arquivo de configuração, preservando
+// comentários e a ordem das demais chaves. Um arquivo vazio recebe apenas a chave.
+func SetValue(data []byte, key, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to set %s: the configuration is not a mapping", key)
+	}
+
+	scalar := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if _, existing := mappingEntry(root, key); existing != nil {
+		scalar.LineComment = existing.LineComment
+	}
+	setMappingEntry(root, key, scalar)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalize converte os atalhos do formato plano para o formato canônico diretamente nos nós YAML.
+// Retorna false se nada foi alterado.
+func canonicalize(root *yaml.Node) bool {
+	if root.Kind != yaml.MappingNode {
+		return false
+	}
+
+	changed := false
+	// name -> project
+	if key, _ := mappingEntry(root, "name"); key != nil {
+		if project, _ := mappingEntry(root, "project"); project == nil {
+			key.Value = "project"
+		} else {
+			removeMappingEntry(root, "name")
+		}
+		changed = true
+	}
+
+	// php: 8.3 -> php: {version: 8.3}
+	for _, service := range versionShorthandKeys {
+		if key, value := mappingEntry(root, service); value != nil && value.Kind == yaml.ScalarNode && value.Tag != "!!null" {
+			expandShorthand(root, key, "version")
+			changed = true
+		}
+	}
+
+	// swoole: true -> swoole: {enabled: true}
+	swooleKey, swoole := mappingEntry(root, "swoole")
+	if swoole != nil && swoole.Kind == yaml.ScalarNode {
+		swoole = expandShorthand(root, swooleKey, "enabled")
+		changed = true
+	}
+
+	// openswoole/swoole_port -> swoole: {enabled, port}
+	openKey, openValue := mappingEntry(root, "openswoole")
+	portKey, portValue := mappingEntry(root, "swoole_port")
+	if openKey != nil || portKey != nil {
+		if swoole == nil {
+			swoole = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			swooleKey = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "swoole"}
+			if openKey != nil {
+				swooleKey.HeadComment, swooleKey.LineComment = openKey.HeadComment, openKey.LineComment+openValue.LineComment
+			}
+			root.Content = append(root.Content, swooleKey, swoole)
+		}
+		if openValue != nil {
+			setMappingEntry(swoole, "enabled", detachComments(openValue))
+			removeMappingEntry(root, "openswoole")
+		}
+		if portValue != nil {
+			setMappingEntry(swoole, "port", detachComments(portValue))
+			removeMappingEntry(root, "swoole_port")
+		}
+		changed = true
+	}
+
+	return changed
+}
+
+// expandShorthand transforma "chave: valor" em "chave: {campo: valor}", mantendo o comentário da linha.
+// O nó do valor é reaproveitado dentro do novo mapping, que é retornado.
+func expandShorthand(mapping, key *yaml.Node, field string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i] != key {
+			continue
+		}
+		value := mapping.Content[i+1]
+		if key.LineComment == "" {
+			key.LineComment = value.LineComment
+		}
+		mapping.Content[i+1] = mappingNode(field, detachComments(value))
+		return mapping.Content[i+1]
+	}
+	return nil
+}
+
+// mappingEntry retorna a chave e o valor de uma entrada do mapping
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// setMappingEntry define o valor de uma chave do mapping, adicionando-a se necessário
+func setMappingEntry(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// removeMappingEntry remove uma chave do mapping
+func removeMappingEntry(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// mappingNode cria um mapping com uma única entrada
+func mappingNode(key string, value *yaml.Node) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			value,
+		},
+	}
+}
+
+// detachComments remove os comentários de um escalar, que permanecem na chave original
+func detachComments(node *yaml.Node) *yaml.Node {
+	node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+	return node
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultNodeVersion versão do Node.js usada pelo Hyvä e pelo PWA Studio quando node não é configurado
+const DefaultNodeVersion = "20"
+
+// hyvaPackagePrefix prefixo dos pacotes composer do Hyvä
+const hyvaPackagePrefix = "hyva-themes/"
+
+// HyvaTheme tema Hyvä com uma configuração Tailwind
+type HyvaTheme struct {
+	Name string // Vendor/tema
+	Dir  string // diretório web/tailwind, relativo à raiz do projeto
+}
+
+// DetectHyva verifica se o projeto usa Hyvä: pelos pacotes instalados no composer.lock ou,
+// sem ele, pelos pacotes exigidos no composer.json
+func DetectHyva(projectPath string) bool {
+	if data, err := os.ReadFile(filepath.Join(projectPath, "composer.lock")); err == nil {
+		var lock composerLock
+		if json.Unmarshal(data, &lock) == nil {
+			for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+				if strings.HasPrefix(pkg.Name, hyvaPackagePrefix) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, "composer.json"))
+	if err != nil {
+		return false
+	}
+	var composer ComposerJSON
+	if json.Unmarshal(data, &composer) != nil {
+		return false
+	}
+	for name := range composer.Require {
+		if strings.HasPrefix(name, hyvaPackagePrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindHyvaThemes retorna os temas Hyvä do projeto (app/design/frontend/Vendor/tema/web/tailwind)
+// ou, se não houver nenhum, o tema padrão instalado pelo composer
+func FindHyvaThemes(projectPath string) ([]HyvaTheme, error) {
+	matches, err := filepath.Glob(filepath.Join(projectPath, "app", "design", "frontend", "*", "*", "web", "tailwind", "package.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var themes []HyvaTheme
+	for _, match := range matches {
+		dir, err := filepath.Rel(projectPath, filepath.Dir(match))
+		if err != nil {
+			return nil, err
+		}
+		parts := strings.Split(filepath.ToSlash(dir), "/")
+		themes = append(themes, HyvaTheme{Name: parts[3] + "/" + parts[4], Dir: filepath.ToSlash(dir)})
+	}
+	sort.Slice(themes, func(i, j int) bool { return themes[i].Name < themes[j].Name })
+
+	if len(themes) == 0 {
+		dir := "vendor/hyva-themes/magento2-default-theme/web/tailwind"
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(dir), "package.json")); err == nil {
+			themes = append(themes, HyvaTheme{Name: "Hyva/default", Dir: dir})
+		}
+	}
+
+	return themes, nil
+}
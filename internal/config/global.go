@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GlobalConfigFile é o arquivo de configuração global, no diretório de configuração do usuário
+const GlobalConfigFile = "config.yaml"
+
+// Tipos de chave aceitos para os certificados
+const (
+	KeyTypeECDSA = "ecdsa"
+	KeyTypeRSA   = "rsa"
+)
+
+// GlobalConfig configuração do Deck compartilhada por todos os projetos
+type GlobalConfig struct {
+	Certificates CertificatesConfig `yaml:"certificates,omitempty"`
+	DNS          DNSConfig          `yaml:"dns,omitempty"`
+	Traefik      TraefikConfig      `yaml:"traefik,omitempty"`
+}
+
+// CertificatesConfig configuração da CA local e dos certificados dos projetos
+type CertificatesConfig struct {
+	KeyType string `yaml:"key_type,omitempty"` // ecdsa (padrão) ou rsa
+}
+
+// DNSConfig configuração da resolução dos hostnames dos projetos
+type DNSConfig struct {
+	ManageHosts *bool `yaml:"manage_hosts,omitempty"` // mantém o bloco do Deck no /etc/hosts (padrão: true)
+}
+
+// DefaultTraefikVersion versão da imagem do Traefik usada quando traefik.version não é definida
+const DefaultTraefikVersion = "v3.0"
+
+// TraefikConfig configuração do reverse proxy compartilhado pelos projetos
+type TraefikConfig struct {
+	Version     string          `yaml:"version,omitempty"`      // tag da imagem traefik (padrão: DefaultTraefikVersion)
+	BindAddress string          `yaml:"bind_address,omitempty"` // endereço onde as portas são publicadas (padrão: 127.0.0.1)
+	HTTPPort    int             `yaml:"http_port,omitempty"`    // padrão: 80
+	HTTPSPort   int             `yaml:"https_port,omitempty"`   // padrão: 443
+	Dashboard   DashboardConfig `yaml:"dashboard,omitempty"`
+}
+
+// DashboardConfig configuração do dashboard do Traefik, servido com autenticação básica
+type DashboardConfig struct {
+	Enabled  *bool  `yaml:"enabled,omitempty"`  // padrão: true
+	Host     string `yaml:"host,omitempty"`     // padrão: traefik.test
+	Port     int    `yaml:"port,omitempty"`     // porta opcional para acesso direto, sem TLS
+	User     string `yaml:"user,omitempty"`     // padrão: admin
+	Password string `yaml:"password,omitempty"` // gerada no primeiro setup quando vazia
+}
+
+// DashboardEnabled informa se o dashboard do Traefik deve ser exposto
+func (g *GlobalConfig) DashboardEnabled() bool {
+	return g.Traefik.Dashboard.Enabled == nil || *g.Traefik.Dashboard.Enabled
+}
+
+// URL retorna a URL HTTPS de um hostname, incluindo a porta quando não é a 443
+func (g *GlobalConfig) URL(host string) string {
+	if g.Traefik.HTTPSPort == 443 {
+		return "https://" + host
+	}
+	return fmt.Sprintf("https://%s:%d", host, g.Traefik.HTTPSPort)
+}
+
+// ManagesHosts informa se o Deck deve manter os hostnames dos projetos no /etc/hosts
+func (g *GlobalConfig) ManagesHosts() bool {
+	return g.DNS.ManageHosts == nil || *g.DNS.ManageHosts
+}
+
+// imageTagPattern tag de imagem Docker válida
+var imageTagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// GlobalConfigPath retorna o caminho do arquivo de configuração global (ex: ~/.config/deck/config.yaml)
+func GlobalConfigPath() (string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, GlobalConfigFile), nil
+}
+
+// LoadGlobalConfig carrega a configuração global; sem o arquivo, retorna os valores padrão
+func LoadGlobalConfig() (*GlobalConfig, error) {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var global GlobalConfig
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&global); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	global.applyDefaults()
+
+	if err := global.validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	return &global, nil
+}
+
+// applyDefaults aplica os valores padrão da configuração global
+func (g *GlobalConfig) applyDefaults() {
+	if g.Certificates.KeyType == "" {
+		g.Certificates.KeyType = KeyTypeECDSA
+	}
+	if g.Traefik.Version == "" {
+		g.Traefik.Version = DefaultTraefikVersion
+	}
+	if g.Traefik.BindAddress == "" {
+		g.Traefik.BindAddress = "127.0.0.1"
+	}
+	if g.Traefik.HTTPPort == 0 {
+		g.Traefik.HTTPPort = 80
+	}
+	if g.Traefik.HTTPSPort == 0 {
+		g.Traefik.HTTPSPort = 443
+	}
+	if g.Traefik.Dashboard.Host == "" {
+		g.Traefik.Dashboard.Host = "traefik.test"
+	}
+	if g.Traefik.Dashboard.User == "" {
+		g.Traefik.Dashboard.User = "admin"
+	}
+}
+
+// validate verifica os valores da configuração global
+func (g *GlobalConfig) validate() error {
+	switch g.Certificates.KeyType {
+	case KeyTypeECDSA, KeyTypeRSA:
+	default:
+		return fmt.Errorf("certificates.key_type: must be %q or %q, got %q", KeyTypeECDSA, KeyTypeRSA, g.Certificates.KeyType)
+	}
+
+	if !imageTagPattern.MatchString(g.Traefik.Version) {
+		return fmt.Errorf("traefik.version: %q is not a valid image tag", g.Traefik.Version)
+	}
+
+	if net.ParseIP(g.Traefik.BindAddress) == nil {
+		return fmt.Errorf("traefik.bind_address: %q is not an IP address", g.Traefik.BindAddress)
+	}
+
+	ports := map[int]string{}
+	for _, port := range []struct {
+		field string
+		value int
+	}{
+		{"traefik.http_port", g.Traefik.HTTPPort},
+		{"traefik.https_port", g.Traefik.HTTPSPort},
+		{"traefik.dashboard.port", g.Traefik.Dashboard.Port},
+	} {
+		if port.value == 0 {
+			continue
+		}
+		if port.value < 1 || port.value > 65535 {
+			return fmt.Errorf("%s: %d is not a valid port", port.field, port.value)
+		}
+		if other, ok := ports[port.value]; ok {
+			return fmt.Errorf("%s: port %d is already used by %s", port.field, port.value, other)
+		}
+		ports[port.value] = port.field
+	}
+
+	if !hostnamePattern.MatchString(g.Traefik.Dashboard.Host) {
+		return fmt.Errorf("traefik.dashboard.host: %q is not a valid hostname", g.Traefik.Dashboard.Host)
+	}
+	if strings.Contains(g.Traefik.Dashboard.User, ":") {
+		return fmt.Errorf("traefik.dashboard.user: must not contain ':'")
+	}
+
+	return nil
+}
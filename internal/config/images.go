@@ -0,0 +1,22 @@
+package config
+
+// KnownVersions versões de imagem Docker conhecidas para cada serviço
+var KnownVersions = map[string][]string{
+	"php":        {"7.4", "8.1", "8.2", "8.3", "8.4"},
+	"nginx":      {"1.22", "1.24", "1.26", "1.27", "1.28"},
+	"mariadb":    {"10.4", "10.5", "10.6", "10.11", "11.4"},
+	"opensearch": {"1", "1.2", "1.3", "2", "2.5", "2.11", "2.12", "2.19", "3"},
+	"redis":      {"6.2", "7.0", "7.2", "7.4"},
+	"rabbitmq":   {"3.9", "3.11", "3.12", "3.13", "4.0", "4.1"},
+	"node":       {"18", "20", "22", "24"},
+}
+
+// IsKnownVersion verifica se existe uma imagem conhecida para a versão do serviço
+func IsKnownVersion(service, version string) bool {
+	for _, v := range KnownVersions[service] {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
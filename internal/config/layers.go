@@ -0,0 +1,267 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalConfigFile arquivo opcional (fora do git) mesclado sobre o deck.yaml
+const LocalConfigFile = "deck.local.yaml"
+
+// layer arquivo de configuração já interpolado, validado e no formato canônico
+type layer struct {
+	name         string
+	root         *yaml.Node
+	interpolated map[*yaml.Node][]string // variáveis usadas em cada valor interpolado
+}
+
+// loadLayer lê um arquivo de configuração, interpola variáveis, valida e converte para o formato canônico
+func loadLayer(path string, lookup func(string) (string, bool), partial bool) (*layer, error) {
+	name := filepath.Base(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	l := &layer{name: name, interpolated: make(map[*yaml.Node][]string)}
+	if len(doc.Content) > 0 {
+		l.root = doc.Content[0]
+		if err := l.interpolate(l.root, lookup); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate keys, types, project name and versions before decoding
+	if err := validateDocument(l.root, name, partial); err != nil {
+		return nil, err
+	}
+
+	if l.root != nil {
+		canonicalize(l.root)
+	}
+
+	return l, nil
+}
+
+// interpolate substitui ${VAR} em todos os valores escalares do nó
+func (l *layer) interpolate(node *yaml.Node, lookup func(string) (string, bool)) error {
+	if node.Kind != yaml.ScalarNode {
+		for _, child := range node.Content {
+			if err := l.interpolate(child, lookup); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !strings.Contains(node.Value, "$") {
+		return nil
+	}
+
+	value, vars, err := Interpolate(node.Value, lookup)
+	if err != nil {
+		return fmt.Errorf("%s: line %d, column %d: %w", l.name, node.Line, node.Column, err)
+	}
+	if value == node.Value {
+		return nil
+	}
+
+	node.Value = value
+	if node.Style == 0 {
+		// Valores sem aspas voltam a ter o tipo resolvido pelo YAML (ex: porta como inteiro)
+		node.Tag = ""
+	}
+	if len(vars) > 0 {
+		l.interpolated[node] = vars
+	}
+	return nil
+}
+
+// sources retorna a origem de cada valor definido na camada (ex: "deck.local.yaml via ${XDEBUG_MODE}")
+func (l *layer) sources() map[string]string {
+	sources := make(map[string]string)
+	if l.root == nil {
+		return sources
+	}
+	for path, node := range leafNodes(l.root, "") {
+		source := l.name
+		if vars, ok := l.interpolated[node]; ok {
+			source += " via ${" + strings.Join(vars, "}, ${") + "}"
+		}
+		sources[path] = source
+	}
+	return sources
+}
+
+// leafNodes mapeia o caminho de cada valor (ex: "php.version") para o seu nó. Listas são um único valor.
+func leafNodes(node *yaml.Node, path string) map[string]*yaml.Node {
+	leaves := make(map[string]*yaml.Node)
+	if node.Kind != yaml.MappingNode {
+		leaves[path] = node
+		return leaves
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		for p, n := range leafNodes(node.Content[i+1], joinPath(path, node.Content[i].Value)) {
+			leaves[p] = n
+		}
+	}
+	return leaves
+}
+
+// mergeNodes mescla overlay sobre base: mappings são mesclados recursivamente, outros valores substituídos
+func mergeNodes(base, overlay *yaml.Node) {
+	if base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		*base = *overlay
+		return
+	}
+
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		if _, existing := mappingEntry(base, key.Value); existing != nil {
+			mergeNodes(existing, value)
+			continue
+		}
+		base.Content = append(base.Content, key, value)
+	}
+}
+
+// Interpolate substitui ${VAR}, ${VAR:-default}, ${VAR-default} e ${VAR:?erro} em s.
+// $$ resulta em um $ literal. Retorna também as variáveis usadas.
+func Interpolate(s string, lookup func(string) (string, bool)) (string, []string, error) {
+	var b strings.Builder
+	var vars []string
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+			continue
+		case '{':
+		default:
+			b.WriteByte(s[i])
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", nil, fmt.Errorf("unterminated variable reference in %q", s)
+		}
+		expr := s[i+2 : i+end]
+		i += end
+
+		value, err := expandVariable(expr, lookup)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(value)
+		vars = append(vars, variableName(expr))
+	}
+
+	return b.String(), vars, nil
+}
+
+// expandVariable resolve uma expressão como "VAR:-default"
+func expandVariable(expr string, lookup func(string) (string, bool)) (string, error) {
+	name := variableName(expr)
+	if name == "" {
+		return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+	}
+	value, set := lookup(name)
+	modifier := expr[len(name):]
+
+	switch {
+	case modifier == "":
+		return value, nil
+	case strings.HasPrefix(modifier, ":-"):
+		if value == "" {
+			return modifier[2:], nil
+		}
+	case strings.HasPrefix(modifier, "-"):
+		if !set {
+			return modifier[1:], nil
+		}
+	case strings.HasPrefix(modifier, ":?"):
+		if value == "" {
+			return "", fmt.Errorf("required variable %s is not set: %s", name, modifier[2:])
+		}
+	case strings.HasPrefix(modifier, "?"):
+		if !set {
+			return "", fmt.Errorf("required variable %s is not set: %s", name, modifier[1:])
+		}
+	default:
+		return "", fmt.Errorf("invalid variable reference ${%s}", expr)
+	}
+
+	return value, nil
+}
+
+// variableName extrai o nome da variável do início da expressão
+func variableName(expr string) string {
+	for i, r := range expr {
+		if !(r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9') {
+			return expr[:i]
+		}
+	}
+	return expr
+}
+
+// envLookup busca variáveis no ambiente e, em seguida, no arquivo .env do projeto
+func envLookup(projectDir string) func(string) (string, bool) {
+	dotEnv := loadDotEnv(filepath.Join(projectDir, ".env"))
+	return func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := dotEnv[name]
+		return value, ok
+	}
+}
+
+// loadDotEnv lê um arquivo .env simples (KEY=value), ignorando o arquivo se ele não existir
+func loadDotEnv(path string) map[string]string {
+	values := make(map[string]string)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+
+	return values
+}
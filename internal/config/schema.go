@@ -0,0 +1,11 @@
+package config
+
+import _ "embed"
+
+// SchemaURL endereço público do JSON Schema do deck.yaml, usado para autocompletar em editores
+const SchemaURL = "https://raw.githubusercontent.com/caravelcommerce/deck/main/internal/config/schema/deck.schema.json"
+
+// Schema JSON Schema do deck.yaml
+//
+//go:embed schema/deck.schema.json
+var Schema []byte
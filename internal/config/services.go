@@ -1,15 +1,66 @@
 package config
 
+import (
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
 // ServiceConfig representa configuração de um serviço com versão e configurações customizadas
 type ServiceConfig struct {
 	Version       string                 `yaml:"version"`
 	Configuration map[string]interface{} `yaml:"configuration,omitempty"`
 }
 
+// Modos de execução do Magento (mode)
+const (
+	MageModeDeveloper  = "developer"
+	MageModeProduction = "production"
+	MageModeDefault    = "default"
+)
+
+// MageModes modos aceitos em mode e no 'deck mode'
+var MageModes = []string{MageModeDeveloper, MageModeProduction, MageModeDefault}
+
+// IsMageMode verifica se o valor é um modo válido do Magento
+func IsMageMode(mode string) bool {
+	for _, m := range MageModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Perfis de runtime do PHP (php.profile)
+const (
+	PHPProfileDeveloper   = "developer"
+	PHPProfilePerformance = "performance"
+)
+
 // PHPConfig configuração específica do PHP
 type PHPConfig struct {
-	Version    string   `yaml:"version"`
-	Extensions []string `yaml:"extensions,omitempty"`
+	Version    string            `yaml:"version"`
+	Extensions []string          `yaml:"extensions,omitempty"`
+	Profile    string            `yaml:"profile,omitempty"` // developer (padrão) ou performance
+	INI        map[string]string `yaml:"ini,omitempty"`     // diretivas adicionadas ao php.ini
+	FPM        map[string]string `yaml:"fpm,omitempty"`     // diretivas adicionadas ao pool do PHP-FPM
+}
+
+// Setting diretiva de um arquivo de configuração (ex: "memory_limit = 2G")
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// sortedSettings converte um mapa de diretivas em uma lista ordenada pelo nome
+func sortedSettings(values map[string]string) []Setting {
+	settings := make([]Setting, 0, len(values))
+	for name, value := range values {
+		settings = append(settings, Setting{Name: name, Value: value})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Name < settings[j].Name })
+	return settings
 }
 
 // NginxConfig configuração específica do Nginx
@@ -22,24 +73,43 @@ type NginxConfig struct {
 type MariaDBConfig struct {
 	Version       string                 `yaml:"version"`
 	Configuration map[string]interface{} `yaml:"configuration,omitempty"`
+	Credentials   *DatabaseCredentials   `yaml:"credentials,omitempty"`
+	Expose        *bool                  `yaml:"expose,omitempty"` // acessível do host por uma porta do Traefik (padrão: true)
+}
+
+// DatabaseCredentials credenciais e nome do banco de dados do Magento
+type DatabaseCredentials struct {
+	RootPassword string `yaml:"root_password,omitempty"`
+	Database     string `yaml:"database,omitempty"`
+	User         string `yaml:"user,omitempty"`
+	Password     string `yaml:"password,omitempty"`
 }
 
 // OpenSearchConfig configuração específica do OpenSearch
 type OpenSearchConfig struct {
 	Version       string                 `yaml:"version"`
 	Configuration map[string]interface{} `yaml:"configuration,omitempty"`
+	Dashboards    bool                   `yaml:"dashboards,omitempty"` // OpenSearch Dashboards em search.{project}.test
 }
 
 // RedisConfig configuração específica do Redis
 type RedisConfig struct {
 	Version       string                 `yaml:"version"`
 	Configuration map[string]interface{} `yaml:"configuration,omitempty"`
+	Expose        *bool                  `yaml:"expose,omitempty"` // acessível do host por uma porta do Traefik (padrão: true)
 }
 
 // RabbitMQConfig configuração específica do RabbitMQ
 type RabbitMQConfig struct {
 	Version       string                 `yaml:"version"`
 	Configuration map[string]interface{} `yaml:"configuration,omitempty"`
+	Credentials   *RabbitMQCredentials   `yaml:"credentials,omitempty"`
+}
+
+// RabbitMQCredentials usuário e senha do RabbitMQ
+type RabbitMQCredentials struct {
+	User     string `yaml:"user,omitempty"`
+	Password string `yaml:"password,omitempty"`
 }
 
 // NodeConfig configuração específica do Node.js
@@ -53,6 +123,33 @@ type SwooleConfig struct {
 	Port    int  `yaml:"port,omitempty"`
 }
 
+// PWAConfig dev server do PWA Studio, em pwa.{project}.test
+type PWAConfig struct {
+	Path       string `yaml:"path,omitempty"`        // diretório do app, relativo à raiz do projeto (padrão: pwa-studio)
+	Port       int    `yaml:"port,omitempty"`        // porta do dev server (padrão: 8080)
+	Command    string `yaml:"command,omitempty"`     // comando que inicia o dev server (padrão: yarn watch)
+	BackendURL string `yaml:"backend_url,omitempty"` // MAGENTO_BACKEND_URL (padrão: o Nginx do projeto)
+}
+
+// Ferramentas disponíveis para tools.database
+const (
+	DatabaseToolAdminer    = "adminer"
+	DatabaseToolPhpMyAdmin = "phpmyadmin"
+)
+
+// ToolsConfig ferramentas web opcionais para inspecionar os serviços do projeto
+type ToolsConfig struct {
+	Database     string `yaml:"database,omitempty"`     // adminer ou phpmyadmin, em db.{project}.test
+	RedisInsight bool   `yaml:"redisinsight,omitempty"` // RedisInsight em redis.{project}.test
+}
+
+// DomainConfig hostname do projeto, opcionalmente ligado a um website ou store view do Magento
+type DomainConfig struct {
+	Host    string `yaml:"host"`
+	RunCode string `yaml:"run_code,omitempty"` // MAGE_RUN_CODE
+	RunType string `yaml:"run_type,omitempty"` // MAGE_RUN_TYPE: website ou store
+}
+
 // Helper functions para obter versões
 func (p *PHPConfig) GetVersion() string {
 	if p != nil && p.Version != "" {
@@ -158,3 +255,70 @@ func (r *RabbitMQConfig) GetConfigValue(key string) interface{} {
 	}
 	return r.Configuration[key]
 }
+
+// decodeShorthand decodifica a forma completa de um serviço ou, se o nó for escalar,
+// a forma abreviada (ex: "php: 8.3" equivale a "php: {version: 8.3}")
+func decodeShorthand(node *yaml.Node, version *string, full interface{}) error {
+	if node.Kind == yaml.ScalarNode {
+		*version = node.Value
+		return nil
+	}
+	return node.Decode(full)
+}
+
+// UnmarshalYAML aceita "php: 8.3" além da forma completa
+func (p *PHPConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain PHPConfig
+	return decodeShorthand(node, &p.Version, (*plain)(p))
+}
+
+// UnmarshalYAML aceita "nginx: 1.28" além da forma completa
+func (n *NginxConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain NginxConfig
+	return decodeShorthand(node, &n.Version, (*plain)(n))
+}
+
+// UnmarshalYAML aceita "mariadb: 11.4" além da forma completa
+func (m *MariaDBConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain MariaDBConfig
+	return decodeShorthand(node, &m.Version, (*plain)(m))
+}
+
+// UnmarshalYAML aceita "opensearch: 3" além da forma completa
+func (o *OpenSearchConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain OpenSearchConfig
+	return decodeShorthand(node, &o.Version, (*plain)(o))
+}
+
+// UnmarshalYAML aceita "redis: 7.4" além da forma completa
+func (r *RedisConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain RedisConfig
+	return decodeShorthand(node, &r.Version, (*plain)(r))
+}
+
+// UnmarshalYAML aceita "rabbitmq: 4.1" além da forma completa
+func (r *RabbitMQConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain RabbitMQConfig
+	return decodeShorthand(node, &r.Version, (*plain)(r))
+}
+
+// UnmarshalYAML aceita "node: 20" além da forma completa
+func (n *NodeConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain NodeConfig
+	return decodeShorthand(node, &n.Version, (*plain)(n))
+}
+
+// UnmarshalYAML aceita "swoole: true" além da forma completa
+func (s *SwooleConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&s.Enabled)
+	}
+	type plain SwooleConfig
+	return node.Decode((*plain)(s))
+}
+
+// UnmarshalYAML aceita "- loja.test" além da forma completa
+func (d *DomainConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain DomainConfig
+	return decodeShorthand(node, &d.Host, (*plain)(d))
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Origens possíveis de um valor da configuração resolvida
+const (
+	SourceDefault = "built-in default"
+)
+
+// magentoSource descreve valores vindos da matriz de compatibilidade do Magento
+func magentoSource(version string) string {
+	return fmt.Sprintf("Magento %s matrix", version)
+}
+
+// setSource registra a origem de um valor (ex: "php.version")
+func (c *DeckConfig) setSource(path, source string) {
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources[path] = source
+}
+
+// Source retorna a origem de um valor da configuração resolvida
+func (c *DeckConfig) Source(path string) string {
+	return c.sources[path]
+}
+
+// Sources retorna a origem de cada valor da configuração resolvida
+func (c *DeckConfig) Sources() map[string]string {
+	return c.sources
+}
+
+// AnnotatedYAML retorna a configuração resolvida em YAML, com a origem de cada valor como comentário
+func (c *DeckConfig) AnnotatedYAML() ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	annotate(&node, "", c.sources)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// annotate adiciona a origem de cada valor como comentário de linha
+func annotate(node *yaml.Node, path string, sources map[string]string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		valuePath := joinPath(path, key.Value)
+		if source, ok := sources[valuePath]; ok {
+			key.LineComment = source
+		}
+		annotate(value, valuePath, sources)
+	}
+}
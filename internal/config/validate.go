@@ -0,0 +1,360 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/magento"
+	"gopkg.in/yaml.v3"
+)
+
+// projectNamePattern nome do projeto precisa ser um label DNS válido (também seguro para nomes de containers)
+var projectNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidationError erro de validação com a posição no arquivo de configuração
+type ValidationError struct {
+	File    string
+	Line    int
+	Column  int
+	Field   string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	position := fmt.Sprintf("%s: line %d, column %d", e.File, e.Line, e.Column)
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", position, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", position, e.Field, e.Message)
+}
+
+// ValidationErrors lista de erros de validação
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = "  " + err.Error()
+	}
+	return "invalid configuration:\n" + strings.Join(messages, "\n")
+}
+
+func (e *ValidationErrors) add(node *yaml.Node, field, format string, args ...interface{}) {
+	*e = append(*e, ValidationError{
+		Line:    node.Line,
+		Column:  node.Column,
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validateDocument valida um arquivo de configuração: chaves desconhecidas, tipos, nome do projeto e versões.
+// Em camadas parciais (deck.local.yaml) o nome do projeto não é obrigatório.
+func validateDocument(root *yaml.Node, file string, partial bool) error {
+	var errs ValidationErrors
+
+	if root == nil {
+		if !partial {
+			errs = append(errs, ValidationError{Line: 1, Column: 1, Field: "project", Message: "required field is missing"})
+		}
+	} else {
+		validateNode(root, reflect.TypeOf(deckConfigYAML{}), "", &errs)
+		if root.Kind == yaml.MappingNode {
+			validateValues(root, partial, &errs)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for i := range errs {
+		errs[i].File = file
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+// validateNode verifica a estrutura do YAML contra os campos e tipos da struct de configuração
+func validateNode(node *yaml.Node, t reflect.Type, path string, errs *ValidationErrors) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if field, ok := shorthandFields[t]; ok && node.Kind == yaml.ScalarNode {
+			validateNode(node, yamlFields(t)[field].Type, path, errs)
+			return
+		}
+		if node.Kind != yaml.MappingNode {
+			errs.add(node, path, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				message := "unknown field"
+				if suggestion := suggestField(key.Value, fields); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				errs.add(key, joinPath(path, key.Value), message)
+				continue
+			}
+			validateNode(value, field.Type, joinPath(path, key.Value), errs)
+		}
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			errs.add(node, path, "expected a list, got %s", describeNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			validateNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			errs.add(node, path, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			validateNode(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), errs)
+		}
+
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			errs.add(node, path, "expected a string, got %s", describeNode(node))
+		}
+
+	case reflect.Bool:
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!bool" {
+			errs.add(node, path, "expected true or false, got %s", describeNode(node))
+		}
+
+	case reflect.Int:
+		if node.Kind != yaml.ScalarNode || node.ShortTag() != "!!int" {
+			errs.add(node, path, "expected an integer, got %s", describeNode(node))
+		}
+	}
+}
+
+// shorthandFields campo preenchido pela forma abreviada escalar de cada tipo (ex: "php: 8.3")
+var shorthandFields = map[reflect.Type]string{
+	reflect.TypeOf(PHPConfig{}):        "version",
+	reflect.TypeOf(NginxConfig{}):      "version",
+	reflect.TypeOf(MariaDBConfig{}):    "version",
+	reflect.TypeOf(OpenSearchConfig{}): "version",
+	reflect.TypeOf(RedisConfig{}):      "version",
+	reflect.TypeOf(RabbitMQConfig{}):   "version",
+	reflect.TypeOf(NodeConfig{}):       "version",
+	reflect.TypeOf(SwooleConfig{}):     "enabled",
+	reflect.TypeOf(DomainConfig{}):     "host",
+}
+
+// hostnamePattern hostname válido (labels DNS separados por ponto)
+var hostnamePattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// validateValues verifica as regras semânticas: nome do projeto e versões conhecidas
+func validateValues(root *yaml.Node, partial bool, errs *ValidationErrors) {
+	project, name := lookupNode(root, "project"), lookupNode(root, "name")
+	switch {
+	case project != nil && name != nil:
+		errs.add(name, "name", "use either project or name, not both")
+	case project == nil && name == nil:
+		if !partial {
+			errs.add(root, "project", "required field is missing")
+		}
+	case project == nil:
+		project = name
+	}
+	if project != nil && !projectNamePattern.MatchString(project.Value) {
+		errs.add(project, "project", "%q is not a valid project name: use lowercase letters, digits and hyphens (max 63 characters), starting and ending with a letter or digit", project.Value)
+	}
+
+	if lookupNode(root, "openswoole") != nil {
+		if swoole := lookupNode(root, "swoole"); swoole != nil {
+			errs.add(swoole, "swoole", "use either swoole or openswoole, not both")
+		}
+	}
+
+	if version := lookupNode(root, "magento"); version != nil && version.Value != "" && magento.GetVersion(version.Value) == nil {
+		errs.add(version, "magento", "unsupported Magento version %q (versions available: %s)", version.Value, strings.Join(magento.GetSupportedVersions(), ", "))
+	}
+
+	if domains := lookupNode(root, "domains"); domains != nil && domains.Kind == yaml.SequenceNode {
+		seen := make(map[string]bool)
+		for i, domain := range domains.Content {
+			path := fmt.Sprintf("domains[%d]", i)
+			host := domain
+			if domain.Kind == yaml.MappingNode {
+				host = lookupNode(domain, "host")
+				if host == nil {
+					errs.add(domain, path+".host", "required field is missing")
+					continue
+				}
+				if runType := lookupNode(domain, "run_type"); runType != nil && runType.Value != "website" && runType.Value != "store" {
+					errs.add(runType, path+".run_type", "must be website or store, got %q", runType.Value)
+				}
+			}
+			if host.Kind != yaml.ScalarNode {
+				continue
+			}
+			if !hostnamePattern.MatchString(host.Value) {
+				errs.add(host, path, "%q is not a valid hostname", host.Value)
+			} else if seen[host.Value] {
+				errs.add(host, path, "duplicate hostname %q", host.Value)
+			}
+			seen[host.Value] = true
+		}
+	}
+
+	if mode := lookupNode(root, "mode"); mode != nil && mode.Kind == yaml.ScalarNode && !IsMageMode(mode.Value) {
+		errs.add(mode, "mode", "must be one of %s, got %q", strings.Join(MageModes, ", "), mode.Value)
+	}
+
+	if profile := lookupNode(root, "php", "profile"); profile != nil && profile.Kind == yaml.ScalarNode && profile.Value != "" &&
+		profile.Value != PHPProfileDeveloper && profile.Value != PHPProfilePerformance {
+		errs.add(profile, "php.profile", "must be %s or %s, got %q", PHPProfileDeveloper, PHPProfilePerformance, profile.Value)
+	}
+
+	if tool := lookupNode(root, "tools", "database"); tool != nil && tool.Kind == yaml.ScalarNode && tool.Value != "" &&
+		tool.Value != DatabaseToolAdminer && tool.Value != DatabaseToolPhpMyAdmin {
+		errs.add(tool, "tools.database", "must be %s or %s, got %q", DatabaseToolAdminer, DatabaseToolPhpMyAdmin, tool.Value)
+	}
+
+	for _, service := range versionShorthandKeys {
+		version := lookupNode(root, service)
+		if version != nil && version.Kind == yaml.MappingNode {
+			version = lookupNode(version, "version")
+		}
+		if version == nil || version.Kind != yaml.ScalarNode || version.Value == "" {
+			continue
+		}
+		if !IsKnownVersion(service, version.Value) {
+			errs.add(version, service+".version", "unknown %s version %q (known versions: %s)", service, version.Value, strings.Join(KnownVersions[service], ", "))
+		}
+	}
+}
+
+// lookupNode retorna o nó no caminho informado ou nil se não existir
+func lookupNode(node *yaml.Node, path ...string) *yaml.Node {
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// yamlFields mapeia o nome YAML de cada campo da struct para o campo
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("yaml")
+		if field.Anonymous && strings.Contains(tag, ",inline") {
+			for name, inlined := range yamlFields(field.Type) {
+				fields[name] = inlined
+			}
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// suggestField sugere o campo conhecido mais próximo de uma chave desconhecida
+func suggestField(key string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := levenshtein(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("%q", node.Value)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// SanitizeProjectName converte um nome qualquer (ex: nome do diretório) em um nome de projeto válido
+func SanitizeProjectName(name string) string {
+	name = strings.ToLower(name)
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "magento"
+	}
+	return name
+}
@@ -0,0 +1,179 @@
+package dns
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// HostsFile é o arquivo de hosts do sistema
+const HostsFile = "/etc/hosts"
+
+// Marcadores do bloco de /etc/hosts gerenciado pelo Deck
+const (
+	beginMarker = "# BEGIN deck - managed by 'deck dns', do not edit"
+	endMarker   = "# END deck"
+)
+
+// Address é o endereço para onde os hostnames dos projetos apontam
+const Address = "127.0.0.1"
+
+// ManagedHosts retorna os hostnames presentes no bloco gerenciado do arquivo de hosts
+func ManagedHosts() ([]string, error) {
+	data, err := os.ReadFile(HostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", HostsFile, err)
+	}
+
+	block, _, _, found := findBlock(data)
+	if !found {
+		return nil, nil
+	}
+
+	var hosts []string
+	for _, line := range strings.Split(string(block), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		hosts = append(hosts, fields[1:]...)
+	}
+	return hosts, nil
+}
+
+// Sync reescreve o bloco gerenciado com os hostnames informados (removendo-o se a lista
+// estiver vazia). O arquivo só é gravado quando o conteúdo muda.
+func Sync(hosts []string) (bool, error) {
+	data, err := os.ReadFile(HostsFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", HostsFile, err)
+	}
+
+	updated := replaceBlock(data, renderBlock(hosts))
+	if bytes.Equal(updated, data) {
+		return false, nil
+	}
+
+	if err := writeHostsFile(updated); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Remove remove o bloco gerenciado do arquivo de hosts
+func Remove() (bool, error) {
+	return Sync(nil)
+}
+
+// Resolves informa se o hostname resolve para o endereço local
+func Resolves(host string) bool {
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+	return false
+}
+
+// renderBlock gera o bloco gerenciado, uma linha por hostname, em ordem alfabética
+func renderBlock(hosts []string) []byte {
+	unique := map[string]bool{}
+	for _, host := range hosts {
+		unique[strings.ToLower(host)] = true
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	sorted := make([]string, 0, len(unique))
+	for host := range unique {
+		sorted = append(sorted, host)
+	}
+	sort.Strings(sorted)
+
+	var b bytes.Buffer
+	b.WriteString(beginMarker + "\n")
+	for _, host := range sorted {
+		fmt.Fprintf(&b, "%s\t%s\n", Address, host)
+	}
+	b.WriteString(endMarker + "\n")
+	return b.Bytes()
+}
+
+// findBlock localiza o bloco gerenciado, retornando seu conteúdo e os offsets de início e fim
+// (incluindo os marcadores e a quebra de linha final)
+func findBlock(data []byte) ([]byte, int, int, bool) {
+	start := bytes.Index(data, []byte(beginMarker))
+	if start < 0 || (start > 0 && data[start-1] != '\n') {
+		return nil, 0, 0, false
+	}
+
+	rel := bytes.Index(data[start:], []byte(endMarker))
+	if rel < 0 {
+		return nil, 0, 0, false
+	}
+	end := start + rel + len(endMarker)
+	if end < len(data) && data[end] == '\n' {
+		end++
+	}
+
+	return data[start+len(beginMarker) : start+rel], start, end, true
+}
+
+// replaceBlock substitui o bloco gerenciado (ou o adiciona ao final do arquivo)
+func replaceBlock(data, block []byte) []byte {
+	if _, start, end, found := findBlock(data); found {
+		result := append([]byte{}, data[:start]...)
+		if len(block) == 0 && end == len(data) && bytes.HasSuffix(result, []byte("\n\n")) {
+			// Remove também a linha em branco adicionada antes do bloco
+			result = result[:len(result)-1]
+		}
+		result = append(result, block...)
+		return append(result, data[end:]...)
+	}
+
+	if len(block) == 0 {
+		return data
+	}
+
+	result := append([]byte{}, data...)
+	if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n")) {
+		result = append(result, '\n')
+	}
+	if len(result) > 0 && !bytes.HasSuffix(result, []byte("\n\n")) {
+		result = append(result, '\n')
+	}
+	return append(result, block...)
+}
+
+// writeHostsFile grava o arquivo de hosts diretamente ou, sem permissão, via 'sudo tee'
+func writeHostsFile(data []byte) error {
+	file, err := os.OpenFile(HostsFile, os.O_WRONLY|os.O_TRUNC, 0)
+	if err == nil {
+		defer file.Close()
+		if _, err := file.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", HostsFile, err)
+		}
+		return nil
+	}
+	if !os.IsPermission(err) {
+		return fmt.Errorf("failed to open %s: %w", HostsFile, err)
+	}
+
+	fmt.Printf("📝 Updating %s (sudo may ask for your password)...\n", HostsFile)
+	cmd := exec.Command("sudo", "tee", HostsFile)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to update %s: %w", HostsFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileChange representa a diferença entre um arquivo gerado e o existente no .deck
+type FileChange struct {
+	Path    string // caminho relativo ao .deck
+	Old     []byte
+	New     []byte
+	Created bool
+	Deleted bool
+}
+
+// Diff retorna o diff unificado da alteração
+func (c FileChange) Diff() string {
+	return UnifiedDiff(c.Path, c.Old, c.New)
+}
+
+// Impact resume os serviços afetados por um conjunto de alterações
+type Impact struct {
+	Rebuild  []string // imagens que precisam ser reconstruídas
+	Recreate []string // containers que precisam ser recriados
+	Restart  []string // containers que só precisam ser reiniciados
+}
+
+// IsEmpty indica se nenhum serviço foi afetado
+func (i *Impact) IsEmpty() bool {
+	return len(i.Rebuild) == 0 && len(i.Recreate) == 0 && len(i.Restart) == 0
+}
+
+// PlanChanges compara os arquivos renderizados com os existentes no .deck. Arquivos do .deck que
+// não são mais gerados (ex: criados por uma versão anterior do Deck) são marcados para remoção.
+func PlanChanges(deckDir string, files map[string][]byte) ([]FileChange, error) {
+	stale, err := staleFiles(deckDir, files)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files)+len(stale))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	paths = append(paths, stale...)
+	sort.Strings(paths)
+
+	var changes []FileChange
+	for _, path := range paths {
+		content := files[path]
+
+		existing, err := os.ReadFile(filepath.Join(deckDir, filepath.FromSlash(path)))
+		if os.IsNotExist(err) {
+			if content != nil {
+				changes = append(changes, FileChange{Path: path, New: content, Created: true})
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if content == nil {
+			changes = append(changes, FileChange{Path: path, Old: existing, Deleted: true})
+			continue
+		}
+
+		if !bytes.Equal(existing, content) {
+			changes = append(changes, FileChange{Path: path, Old: existing, New: content})
+		}
+	}
+
+	return changes, nil
+}
+
+// staleFiles retorna os arquivos existentes no .deck, relativos a ele, que não fazem parte dos arquivos gerados
+func staleFiles(deckDir string, files map[string][]byte) ([]string, error) {
+	var stale []string
+	err := filepath.WalkDir(deckDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == deckDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(deckDir, path)
+		if err != nil {
+			return err
+		}
+		if _, ok := files[filepath.ToSlash(rel)]; !ok {
+			stale = append(stale, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", deckDir, err)
+	}
+	return stale, nil
+}
+
+// ApplyChanges grava no .deck apenas os arquivos alterados
+func ApplyChanges(deckDir string, changes []FileChange) error {
+	for _, change := range changes {
+		path := filepath.Join(deckDir, filepath.FromSlash(change.Path))
+
+		if change.Deleted {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove file %s: %w", path, err)
+			}
+			removeEmptyDirs(deckDir, filepath.Dir(path))
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+		}
+
+		if err := os.WriteFile(path, change.New, 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// removeEmptyDirs remove dir e seus pais que ficaram vazios, sem sair do .deck
+func removeEmptyDirs(deckDir, dir string) {
+	for dir != deckDir && strings.HasPrefix(dir, deckDir+string(filepath.Separator)) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// AnalyzeImpact determina quais serviços precisam ser reconstruídos, recriados ou reiniciados
+func AnalyzeImpact(changes []FileChange) (*Impact, error) {
+	rebuild := map[string]bool{}
+	recreate := map[string]bool{}
+	restart := map[string]bool{}
+
+	for _, change := range changes {
+		switch {
+		case change.Path == "docker-compose.yml" || change.Path == "docker-compose.override.yml":
+			if err := compareComposeServices(change.Old, change.New, rebuild, recreate); err != nil {
+				return nil, err
+			}
+		case change.Path == "php/Dockerfile":
+			rebuild["php"] = true
+		case change.Deleted:
+			// Um arquivo removido deixou de ser montado, o que já aparece como mudança no docker-compose.yml
+		default:
+			// Arquivos montados como volume: basta reiniciar o serviço dono do diretório
+			service := strings.SplitN(change.Path, "/", 2)[0]
+			restart[service] = true
+		}
+	}
+
+	impact := &Impact{}
+	for service := range rebuild {
+		impact.Rebuild = append(impact.Rebuild, service)
+	}
+	for service := range recreate {
+		if !rebuild[service] {
+			impact.Recreate = append(impact.Recreate, service)
+		}
+	}
+	for service := range restart {
+		if !rebuild[service] && !recreate[service] {
+			impact.Restart = append(impact.Restart, service)
+		}
+	}
+	sort.Strings(impact.Rebuild)
+	sort.Strings(impact.Recreate)
+	sort.Strings(impact.Restart)
+
+	return impact, nil
+}
+
+// compareComposeServices compara as definições de cada serviço entre dois docker-compose.yml
+func compareComposeServices(oldContent, newContent []byte, rebuild, recreate map[string]bool) error {
+	var oldCompose, newCompose struct {
+		Services map[string]map[string]interface{} `yaml:"services"`
+	}
+	if len(oldContent) > 0 {
+		if err := yaml.Unmarshal(oldContent, &oldCompose); err != nil {
+			return fmt.Errorf("failed to parse existing docker-compose.yml: %w", err)
+		}
+	}
+	if err := yaml.Unmarshal(newContent, &newCompose); err != nil {
+		return fmt.Errorf("failed to parse generated docker-compose.yml: %w", err)
+	}
+
+	for name, service := range newCompose.Services {
+		previous, ok := oldCompose.Services[name]
+		if !ok {
+			recreate[name] = true
+			continue
+		}
+		if reflect.DeepEqual(previous, service) {
+			continue
+		}
+		if !reflect.DeepEqual(previous["build"], service["build"]) {
+			rebuild[name] = true
+		}
+		recreate[name] = true
+	}
+
+	// Serviços removidos de um override voltam à definição gerada e também precisam ser recriados
+	for name := range oldCompose.Services {
+		if _, ok := newCompose.Services[name]; !ok {
+			recreate[name] = true
+		}
+	}
+
+	return nil
+}
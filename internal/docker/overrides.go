@@ -0,0 +1,198 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OverrideDirs diretórios de override procurados na raiz do projeto, em ordem de prioridade
+var OverrideDirs = []string{"deck", ".deck.d"}
+
+// Overrides descreve as customizações encontradas no diretório de override do projeto
+type Overrides struct {
+	Dir     string // caminho do diretório de override relativo ao .deck (ex: ../deck)
+	Compose []byte // conteúdo do docker-compose.override.yml, se existir
+	Nginx   bool   // nginx/*.conf incluídos no server block do Magento
+	PHP     bool   // php/*.ini carregados após o php.ini gerado
+	MariaDB bool   // mariadb/*.cnf incluídos pelo my.cnf gerado
+}
+
+// FindOverrides procura o diretório de override do projeto, retornando nil se não existir
+func FindOverrides(projectDir string) (*Overrides, error) {
+	for _, name := range OverrideDirs {
+		dir := filepath.Join(projectDir, name)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+
+		overrides := &Overrides{
+			Dir:     "../" + name,
+			Nginx:   isDir(filepath.Join(dir, "nginx")),
+			PHP:     isDir(filepath.Join(dir, "php")),
+			MariaDB: isDir(filepath.Join(dir, "mariadb")),
+		}
+
+		compose, err := os.ReadFile(filepath.Join(dir, "docker-compose.override.yml"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s/docker-compose.override.yml: %w", name, err)
+		}
+		if compose != nil {
+			if compose, err = rebaseComposePaths(compose, overrides.Dir); err != nil {
+				return nil, fmt.Errorf("failed to parse %s/docker-compose.override.yml: %w", name, err)
+			}
+		}
+		overrides.Compose = compose
+
+		return overrides, nil
+	}
+
+	return nil, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// rebaseComposePaths ajusta os caminhos relativos do docker-compose.override.yml, escritos em
+// relação ao diretório de override, para o .deck, para onde o arquivo é copiado (o docker compose
+// resolve os caminhos de todos os arquivos a partir do diretório do docker-compose.yml).
+// O arquivo só é reescrito quando algum caminho muda.
+func rebaseComposePaths(data []byte, dir string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil
+	}
+	root := doc.Content[0]
+
+	changed := false
+	rebase := func(node *yaml.Node) {
+		if node == nil || node.Kind != yaml.ScalarNode {
+			return
+		}
+		if rebased := rebasePath(dir, node.Value); rebased != node.Value {
+			node.Value = rebased
+			changed = true
+		}
+	}
+
+	for _, service := range mappingValues(mappingValue(root, "services")) {
+		// build: ./dir ou build.context
+		if build := mappingValue(service, "build"); build != nil {
+			if build.Kind == yaml.ScalarNode {
+				rebase(build)
+			} else {
+				rebase(mappingValue(build, "context"))
+			}
+		}
+
+		// env_file: arquivo, lista de arquivos ou lista de {path}
+		if envFile := mappingValue(service, "env_file"); envFile != nil {
+			if envFile.Kind == yaml.ScalarNode {
+				rebase(envFile)
+			}
+			for _, item := range sequenceItems(envFile) {
+				if item.Kind == yaml.ScalarNode {
+					rebase(item)
+				} else {
+					rebase(mappingValue(item, "path"))
+				}
+			}
+		}
+
+		// volumes: "./origem:/destino[:modo]" ou {type: bind, source: ./origem}
+		for _, volume := range sequenceItems(mappingValue(service, "volumes")) {
+			if volume.Kind == yaml.ScalarNode {
+				source, target, found := strings.Cut(volume.Value, ":")
+				if found && strings.HasPrefix(source, ".") {
+					if rebased := rebasePath(dir, source); rebased != source {
+						volume.Value = rebased + ":" + target
+						changed = true
+					}
+				}
+				continue
+			}
+			if source := mappingValue(volume, "source"); source != nil && strings.HasPrefix(source.Value, ".") {
+				rebase(source)
+			}
+		}
+
+		if extends := mappingValue(service, "extends"); extends != nil {
+			rebase(mappingValue(extends, "file"))
+		}
+	}
+
+	// configs e secrets de nível superior: {file: ./arquivo}
+	for _, key := range []string{"configs", "secrets"} {
+		for _, item := range mappingValues(mappingValue(root, key)) {
+			rebase(mappingValue(item, "file"))
+		}
+	}
+
+	if !changed {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rebasePath prefixa um caminho relativo com dir; caminhos absolutos, do home, com variáveis no
+// início ou URLs (ex: contexto de build em um repositório git) não mudam
+func rebasePath(dir, p string) string {
+	if p == "" || path.IsAbs(p) || strings.HasPrefix(p, "~") || strings.HasPrefix(p, "$") ||
+		strings.Contains(p, "://") || strings.HasPrefix(p, "git@") {
+		return p
+	}
+	return path.Join(dir, p)
+}
+
+// mappingValue retorna o valor de uma chave de um mapping YAML, ou nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingValues retorna os valores de um mapping YAML
+func mappingValues(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var values []*yaml.Node
+	for i := 1; i < len(node.Content); i += 2 {
+		values = append(values, node.Content[i])
+	}
+	return values
+}
+
+// sequenceItems retorna os itens de uma sequência YAML
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
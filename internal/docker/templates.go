@@ -1,486 +1,219 @@
 package docker
 
 import (
+	"bytes"
+	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/caravelcommerce/deck/internal/config"
 )
 
-const dockerComposeTemplate = `version: '3.8'
+//go:embed templates
+var templatesFS embed.FS
 
-services:
-  nginx:
-    image: nginx:{{.GetNginxVersion}}-alpine
-    container_name: {{.Project}}_nginx
-    volumes:
-      - ../:/var/www/html:cached
-      - ./nginx/nginx.conf:/etc/nginx/nginx.conf:ro
-      - ./nginx/default.conf:/etc/nginx/conf.d/default.conf:ro
-    networks:
-      - {{.Project}}_network
-      - traefik_network
-    labels:
-      - "traefik.enable=true"
-      - "traefik.http.routers.{{.Project}}.rule=Host(` + "`{{.Project}}.test`" + `)"
-      - "traefik.http.routers.{{.Project}}.entrypoints=websecure"
-      - "traefik.http.routers.{{.Project}}.tls=true"
-      - "traefik.http.services.{{.Project}}.loadbalancer.server.port=80"
-    depends_on:
-      - php
-
-  php:
-    build:
-      context: ./php
-      args:
-        PHP_VERSION: {{.GetPHPVersion}}
-        INSTALL_OPENSWOOLE: {{.IsSwooleEnabled}}
-    container_name: {{.Project}}_php
-    volumes:
-      - ../:/var/www/html:cached
-      - ./php/php.ini:/usr/local/etc/php/php.ini:ro
-      - ./php/php-fpm.conf:/usr/local/etc/php-fpm.d/www.conf:ro
-    networks:
-      - {{.Project}}_network{{if gt .SwoolePort 0}}
-      - traefik_network{{end}}
-    environment:
-      - PHP_IDE_CONFIG=serverName={{.Project}}{{if gt .SwoolePort 0}}
-    ports:
-      - "{{.GetSwoolePort}}:{{.GetSwoolePort}}"
-    labels:
-      - "traefik.enable=true"
-      # Swoole HTTP Server on api subdomain
-      - "traefik.http.routers.{{.Project}}-swoole.rule=Host(` + "`api.{{.Project}}.test`" + `)"
-      - "traefik.http.routers.{{.Project}}-swoole.entrypoints=websecure"
-      - "traefik.http.routers.{{.Project}}-swoole.tls=true"
-      - "traefik.http.routers.{{.Project}}-swoole.service={{.Project}}-swoole"
-      - "traefik.http.services.{{.Project}}-swoole.loadbalancer.server.port={{.GetSwoolePort}}"{{end}}
-    depends_on:
-      - mariadb
-      - redis
-      - opensearch
-      - rabbitmq
-
-  mariadb:
-    image: mariadb:{{.GetMariaDBVersion}}
-    container_name: {{.Project}}_mariadb
-    environment:
-      MYSQL_ROOT_PASSWORD: root
-      MYSQL_DATABASE: magento
-      MYSQL_USER: magento
-      MYSQL_PASSWORD: magento
-    volumes:
-      - mariadb_data:/var/lib/mysql
-      - ./mariadb/my.cnf:/etc/mysql/conf.d/custom.cnf:ro
-    networks:
-      - {{.Project}}_network
-    command: --max_allowed_packet=256M
-
-  opensearch:
-    image: opensearchproject/opensearch:{{.GetOpenSearchVersion}}
-    container_name: {{.Project}}_opensearch
-    environment:
-      - discovery.type=single-node
-      - "OPENSEARCH_JAVA_OPTS=-Xms512m -Xmx512m"
-      - "DISABLE_SECURITY_PLUGIN=true"
-    volumes:
-      - opensearch_data:/usr/share/opensearch/data
-    networks:
-      - {{.Project}}_network
-
-  redis:
-    image: redis:{{.GetRedisVersion}}-alpine
-    container_name: {{.Project}}_redis
-    volumes:
-      - redis_data:/data
-    networks:
-      - {{.Project}}_network
-
-  rabbitmq:
-    image: rabbitmq:{{.GetRabbitMQVersion}}-management-alpine
-    container_name: {{.Project}}_rabbitmq
-    environment:
-      RABBITMQ_DEFAULT_USER: guest
-      RABBITMQ_DEFAULT_PASS: guest
-    volumes:
-      - rabbitmq_data:/var/lib/rabbitmq
-    networks:
-      - {{.Project}}_network
-
-networks:
-  {{.Project}}_network:
-    driver: bridge
-  traefik_network:
-    external: true
-
-volumes:
-  mariadb_data:
-  opensearch_data:
-  redis_data:
-  rabbitmq_data:
-`
-
-const nginxConfTemplate = `user nginx;
-worker_processes auto;
-error_log /var/log/nginx/error.log warn;
-pid /var/run/nginx.pid;
-
-events {
-    worker_connections 1024;
+// TemplateData dados disponíveis para todos os templates
+type TemplateData struct {
+	config.DeckConfig
+	Overrides Overrides
+	TCPPorts  map[string]int // portas do host alocadas para os serviços TCP expostos pelo Traefik
+	Hyva      bool           // projeto usa um tema Hyvä (habilita o serviço node para o Tailwind)
 }
 
-http {
-    include /etc/nginx/mime.types;
-    default_type application/octet-stream;
-
-    log_format main '$remote_addr - $remote_user [$time_local] "$request" '
-                    '$status $body_bytes_sent "$http_referer" '
-                    '"$http_user_agent" "$http_x_forwarded_for"';
-
-    access_log /var/log/nginx/access.log main;
-
-    sendfile on;
-    tcp_nopush on;
-    tcp_nodelay on;
-    keepalive_timeout 65;
-    types_hash_max_size 2048;
-    client_max_body_size 256M;
-
-    gzip on;
-    gzip_disable "msie6";
-    gzip_vary on;
-    gzip_proxied any;
-    gzip_comp_level 6;
-    gzip_types text/plain text/css text/xml text/javascript application/json application/javascript application/xml+rss;
-
-    include /etc/nginx/conf.d/*.conf;
+// dockerFiles arquivos gerados no .deck; o template de cada um é templates/<arquivo>.tmpl
+var dockerFiles = []string{
+	"docker-compose.yml",
+	"nginx/nginx.conf",
+	"nginx/default.conf",
+	"php/Dockerfile",
+	"php/php.ini",
+	"php/php-fpm.conf",
+	"mariadb/my.cnf",
+	"livereload/server.js",
+	"adminer/login.php",
 }
-`
 
-const nginxDefaultConfTemplate = `upstream fastcgi_backend {
-    server php:9000;
+// templateFuncs funções auxiliares disponíveis nos templates
+var templateFuncs = template.FuncMap{
+	"join":     strings.Join,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"replace":  strings.ReplaceAll,
+	"contains": strings.Contains,
+	"quote":    strconv.Quote,
+	// composeQuote gera um valor entre aspas para o docker-compose.yml, escapando a interpolação do compose
+	"composeQuote": func(s string) string {
+		return strconv.Quote(strings.ReplaceAll(s, "$", "$$"))
+	},
+	// phpQuote gera uma string PHP entre aspas simples
+	"phpQuote": func(s string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+	},
+	// hostRule gera a regra de roteamento do Traefik para um ou mais hostnames
+	"hostRule": func(hosts ...interface{}) string {
+		var rules []string
+		for _, host := range hosts {
+			switch h := host.(type) {
+			case string:
+				rules = append(rules, "Host(`"+h+"`)")
+			case []string:
+				for _, name := range h {
+					rules = append(rules, "Host(`"+name+"`)")
+				}
+			}
+		}
+		return strings.Join(rules, " || ")
+	},
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || value == "" || value == 0 || value == false {
+			return def
+		}
+		return value
+	},
 }
 
-server {
-    listen 80;
-    server_name {{.Project}}.test;
-
-    set $MAGE_ROOT /var/www/html;
-    set $MAGE_MODE developer;
-
-    root $MAGE_ROOT/pub;
-
-    index index.php;
-    autoindex off;
-    charset UTF-8;
-
-    location /setup {
-        root $MAGE_ROOT;
-        location ~ ^/setup/index.php {
-            fastcgi_pass fastcgi_backend;
-            fastcgi_index index.php;
-            fastcgi_param SCRIPT_FILENAME $document_root$fastcgi_script_name;
-            include fastcgi_params;
-        }
-
-        location ~ ^/setup/(?!pub/). {
-            deny all;
-        }
-
-        location ~ ^/setup/pub/ {
-            add_header X-Frame-Options "SAMEORIGIN";
-        }
-    }
-
-    location /update {
-        root $MAGE_ROOT;
-
-        location ~ ^/update/index.php {
-            fastcgi_split_path_info ^(/update/index.php)(/.+)$;
-            fastcgi_pass fastcgi_backend;
-            fastcgi_index index.php;
-            fastcgi_param SCRIPT_FILENAME $document_root$fastcgi_script_name;
-            fastcgi_param PATH_INFO $fastcgi_path_info;
-            include fastcgi_params;
-        }
-
-        location ~ ^/update/(?!pub/). {
-            deny all;
-        }
-
-        location ~ ^/update/pub/ {
-            add_header X-Frame-Options "SAMEORIGIN";
-        }
-    }
-
-    location / {
-        try_files $uri $uri/ /index.php$is_args$args;
-    }
-
-    location /pub/ {
-        location ~ ^/pub/media/(downloadable|customer|import|theme_customization/.*\.xml) {
-            deny all;
-        }
-        alias $MAGE_ROOT/pub/;
-        add_header X-Frame-Options "SAMEORIGIN";
-    }
-
-    location /static/ {
-        expires max;
-
-        location ~ ^/static/version {
-            rewrite ^/static/(version\d*/)?(.*)$ /static/$2 last;
-        }
-
-        location ~* \.(ico|jpg|jpeg|png|gif|svg|js|css|swf|eot|ttf|otf|woff|woff2)$ {
-            add_header Cache-Control "public";
-            add_header X-Frame-Options "SAMEORIGIN";
-            expires +1y;
-
-            if (!-f $request_filename) {
-                rewrite ^/static/(version\d*/)?(.*)$ /static.php?resource=$2 last;
-            }
-        }
-
-        location ~* \.(zip|gz|gzip|bz2|csv|xml)$ {
-            add_header Cache-Control "no-store";
-            add_header X-Frame-Options "SAMEORIGIN";
-            expires off;
-
-            if (!-f $request_filename) {
-               rewrite ^/static/(version\d*/)?(.*)$ /static.php?resource=$2 last;
-            }
-        }
-
-        if (!-f $request_filename) {
-            rewrite ^/static/(version\d*/)?(.*)$ /static.php?resource=$2 last;
-        }
-
-        add_header X-Frame-Options "SAMEORIGIN";
-    }
-
-    location /media/ {
-        try_files $uri $uri/ /get.php$is_args$args;
-
-        location ~ ^/media/theme_customization/.*\.xml {
-            deny all;
-        }
-
-        location ~* \.(ico|jpg|jpeg|png|gif|svg|js|css|swf|eot|ttf|otf|woff|woff2)$ {
-            add_header Cache-Control "public";
-            add_header X-Frame-Options "SAMEORIGIN";
-            expires +1y;
-            try_files $uri $uri/ /get.php$is_args$args;
-        }
-
-        location ~* \.(zip|gz|gzip|bz2|csv|xml)$ {
-            add_header Cache-Control "no-store";
-            add_header X-Frame-Options "SAMEORIGIN";
-            expires off;
-            try_files $uri $uri/ /get.php$is_args$args;
-        }
-
-        add_header X-Frame-Options "SAMEORIGIN";
-    }
-
-    location /media/customer/ {
-        deny all;
-    }
-
-    location /media/downloadable/ {
-        deny all;
-    }
-
-    location /media/import/ {
-        deny all;
-    }
-
-    location ~ /media/theme_customization/.*\.xml$ {
-        deny all;
-    }
-
-    location ~ cron\.php {
-        deny all;
-    }
-
-    location ~ (index|get|static|report|404|503|health_check)\.php$ {
-        try_files $uri =404;
-        fastcgi_pass fastcgi_backend;
-        fastcgi_buffers 1024 4k;
-
-        fastcgi_param PHP_FLAG "session.auto_start=off \n suhosin.session.cryptua=off";
-        fastcgi_param PHP_VALUE "memory_limit=2G \n max_execution_time=18000";
-        fastcgi_read_timeout 600s;
-        fastcgi_connect_timeout 600s;
-
-        fastcgi_index index.php;
-        fastcgi_param SCRIPT_FILENAME $document_root$fastcgi_script_name;
-        include fastcgi_params;
-    }
-
-    location ~ \.php$ {
-        deny all;
-    }
+// TemplateNames retorna os nomes dos templates usados na geração do .deck
+func TemplateNames() []string {
+	names := make([]string, len(dockerFiles))
+	for i, file := range dockerFiles {
+		names[i] = file + ".tmpl"
+	}
+	return names
 }
-`
-
-const phpDockerfileTemplate = `ARG PHP_VERSION
-FROM php:$` + `{PHP_VERSION}-fpm-alpine
 
-ARG INSTALL_OPENSWOOLE=false
-
-RUN apk add --no-cache \
-    freetype-dev \
-    libjpeg-turbo-dev \
-    libpng-dev \
-    libxml2-dev \
-    libxslt-dev \
-    libzip-dev \
-    icu-dev \
-    oniguruma-dev \
-    bash \
-    git \
-    patch \
-    $` + `{INSTALL_OPENSWOOLE:+postgresql-dev} \
-    $` + `{INSTALL_OPENSWOOLE:+autoconf} \
-    $` + `{INSTALL_OPENSWOOLE:+g++} \
-    $` + `{INSTALL_OPENSWOOLE:+make} \
-    && docker-php-ext-configure gd --with-freetype --with-jpeg \
-    && docker-php-ext-install -j$(nproc) \
-        bcmath \
-        gd \
-        intl \
-        mbstring \
-        opcache \
-        pdo_mysql \
-        soap \
-        sockets \
-        xsl \
-        zip
-
-# Install OpenSwoole if enabled
-RUN if [ "$` + `INSTALL_OPENSWOOLE" = "true" ]; then \
-        pecl install openswoole \
-        && docker-php-ext-enable openswoole \
-        && echo "openswoole.use_shortname = 'Off'" >> /usr/local/etc/php/conf.d/docker-php-ext-openswoole.ini; \
-    fi
+// TemplateDirs retorna os diretórios onde templates customizados são procurados, em ordem de prioridade:
+// deck/templates (ou .deck.d/templates) no projeto e templates no diretório de configuração do usuário
+func TemplateDirs(projectDir string) []string {
+	var dirs []string
+	for _, name := range OverrideDirs {
+		dirs = append(dirs, filepath.Join(projectDir, name, "templates"))
+	}
+	if userDir, err := config.UserConfigDir(); err == nil {
+		dirs = append(dirs, filepath.Join(userDir, "templates"))
+	}
+	return dirs
+}
 
-# Install Composer
-COPY --from=composer:latest /usr/bin/composer /usr/bin/composer
+// LoadTemplate carrega um template seguindo a cadeia de busca, com fallback para o template embutido.
+// Retorna também a origem do template (caminho do arquivo ou "embedded").
+func LoadTemplate(name, projectDir string) (string, string, error) {
+	for _, dir := range TemplateDirs(projectDir) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		content, err := os.ReadFile(path)
+		if err == nil {
+			return string(content), path, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+	}
 
-WORKDIR /var/www/html
+	content, err := templatesFS.ReadFile("templates/" + name)
+	if err != nil {
+		return "", "", fmt.Errorf("template %s not found: %w", name, err)
+	}
+	return string(content), "embedded", nil
+}
 
-CMD ["php-fpm"]
-`
+// ExportTemplates grava os templates padrão em dir para customização.
+// Arquivos existentes só são sobrescritos quando force é verdadeiro.
+func ExportTemplates(dir string, force bool) ([]string, error) {
+	var written []string
 
-const phpIniTemplate = `memory_limit = 4G
-max_execution_time = 1800
-zlib.output_compression = On
-upload_max_filesize = 256M
-post_max_size = 256M
+	err := fs.WalkDir(templatesFS, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
 
-opcache.enable = 1
-opcache.enable_cli = 1
-opcache.memory_consumption = 512
-opcache.interned_strings_buffer = 16
-opcache.max_accelerated_files = 100000
-opcache.validate_timestamps = 1
-opcache.revalidate_freq = 2
-opcache.save_comments = 1
-`
+		target := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(path, "templates/")))
+		if _, err := os.Stat(target); err == nil && !force {
+			return nil
+		}
 
-const phpFpmConfTemplate = `[www]
-user = www-data
-group = www-data
-listen = 9000
-pm = dynamic
-pm.max_children = 50
-pm.start_servers = 10
-pm.min_spare_servers = 5
-pm.max_spare_servers = 20
-pm.max_requests = 500
-`
+		content, err := templatesFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return fmt.Errorf("failed to write template %s: %w", target, err)
+		}
 
-const mariadbConfTemplate = `[mysqld]
-innodb_buffer_pool_size = 1G
-innodb_log_file_size = 256M
-innodb_flush_log_at_trx_commit = 2
-innodb_flush_method = O_DIRECT
-max_allowed_packet = 256M
-table_open_cache = 4096
-query_cache_type = 0
-query_cache_size = 0
-`
+		written = append(written, target)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export templates: %w", err)
+	}
 
-type TemplateData struct {
-	config.DeckConfig
+	return written, nil
 }
 
-func GenerateDockerFiles(cfg *config.DeckConfig, deckDir string) error {
-	// Create directories
-	dirs := []string{
-		filepath.Join(deckDir, "nginx"),
-		filepath.Join(deckDir, "php"),
-		filepath.Join(deckDir, "mariadb"),
+// RenderDockerFiles renderiza todos os arquivos Docker em memória, indexados pelo caminho relativo ao .deck.
+// Arquivos com conteúdo nil não devem existir no .deck (ex: override removido pelo usuário).
+func RenderDockerFiles(cfg *config.DeckConfig, projectDir string, tcpPorts map[string]int) (map[string][]byte, error) {
+	overrides, err := FindOverrides(projectDir)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
-		}
+	data := &TemplateData{DeckConfig: *cfg, TCPPorts: tcpPorts, Hyva: config.DetectHyva(projectDir)}
+	if overrides != nil {
+		data.Overrides = *overrides
 	}
 
-	data := TemplateData{DeckConfig: *cfg}
+	files := make(map[string][]byte, len(dockerFiles))
+	for _, file := range dockerFiles {
+		tmplStr, _, err := LoadTemplate(file+".tmpl", projectDir)
+		if err != nil {
+			return nil, err
+		}
 
-	// Generate docker-compose.yml
-	if err := generateFile(filepath.Join(deckDir, "docker-compose.yml"), dockerComposeTemplate, data); err != nil {
-		return err
+		content, err := renderFile(file, tmplStr, data)
+		if err != nil {
+			return nil, err
+		}
+		files[file] = content
 	}
 
-	// Generate nginx configs
-	if err := generateFile(filepath.Join(deckDir, "nginx", "nginx.conf"), nginxConfTemplate, data); err != nil {
-		return err
-	}
-	if err := generateFile(filepath.Join(deckDir, "nginx", "default.conf"), nginxDefaultConfTemplate, data); err != nil {
-		return err
+	// O servidor do LiveReload só existe quando habilitado
+	if !cfg.IsLiveReloadEnabled() {
+		files["livereload/server.js"] = nil
 	}
 
-	// Generate PHP configs
-	if err := generateFile(filepath.Join(deckDir, "php", "Dockerfile"), phpDockerfileTemplate, data); err != nil {
-		return err
-	}
-	if err := generateFile(filepath.Join(deckDir, "php", "php.ini"), phpIniTemplate, data); err != nil {
-		return err
-	}
-	if err := generateFile(filepath.Join(deckDir, "php", "php-fpm.conf"), phpFpmConfTemplate, data); err != nil {
-		return err
+	// O plugin de login só é usado pelo Adminer
+	if cfg.GetDatabaseTool() != config.DatabaseToolAdminer {
+		files["adminer/login.php"] = nil
 	}
 
-	// Generate MariaDB config
-	if err := generateFile(filepath.Join(deckDir, "mariadb", "my.cnf"), mariadbConfTemplate, data); err != nil {
-		return err
-	}
+	// O docker compose mescla automaticamente o docker-compose.override.yml com o docker-compose.yml
+	files["docker-compose.override.yml"] = data.Overrides.Compose
 
-	return nil
+	return files, nil
 }
 
-func generateFile(path, tmplStr string, data interface{}) error {
-	tmpl, err := template.New(filepath.Base(path)).Parse(tmplStr)
-	if err != nil {
-		return fmt.Errorf("failed to parse template for %s: %w", path, err)
-	}
-
-	f, err := os.Create(path)
+func renderFile(path, tmplStr string, data interface{}) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(tmplStr)
 	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", path, err)
+		return nil, fmt.Errorf("failed to parse template for %s: %w", path, err)
 	}
-	defer f.Close()
 
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to execute template for %s: %w", path, err)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template for %s: %w", path, err)
 	}
 
-	return nil
+	// Um template que renderiza vazio ainda gera o arquivo: nil indica arquivo que não deve existir
+	return append([]byte{}, buf.Bytes()...), nil
 }
@@ -0,0 +1,375 @@
+package magento
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// version versão do Magento decomposta para comparação (ex: 2.4.8-p3, 2.4.9-beta1)
+type version struct {
+	numbers  [3]int
+	patch    int    // número do -pN
+	pre      string // alpha, beta ou rc
+	preLevel int    // número da pré-release (ex: beta1 -> 1)
+}
+
+// preReleaseOrder ordem das pré-releases, todas anteriores à versão final
+var preReleaseOrder = map[string]int{"alpha": 1, "beta": 2, "rc": 3}
+
+// parseVersion interpreta uma versão completa ou parcial (ex: "2.4", "v2.4.8-p3", "2.4.9-beta1")
+func parseVersion(s string) (version, bool) {
+	var v version
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v")
+	if s == "" {
+		return v, false
+	}
+
+	base, suffix, _ := strings.Cut(s, "-")
+	parts := strings.Split(base, ".")
+	if len(parts) > 4 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		if i < 3 {
+			v.numbers[i] = n
+		}
+	}
+
+	switch {
+	case suffix == "":
+	case strings.HasPrefix(suffix, "p"):
+		n, err := strconv.Atoi(suffix[1:])
+		if err != nil {
+			return v, false
+		}
+		v.patch = n
+	default:
+		for name := range preReleaseOrder {
+			if strings.HasPrefix(suffix, name) {
+				v.pre = name
+				v.preLevel, _ = strconv.Atoi(strings.TrimLeft(suffix[len(name):], ".-"))
+				return v, true
+			}
+		}
+		return v, false
+	}
+	return v, true
+}
+
+// compare retorna -1, 0 ou 1; pré-releases vêm antes da versão final e patches (-pN) depois
+func (v version) compare(o version) int {
+	for i := range v.numbers {
+		if v.numbers[i] != o.numbers[i] {
+			return sign(v.numbers[i] - o.numbers[i])
+		}
+	}
+	if v.pre != o.pre {
+		switch {
+		case v.pre == "":
+			return 1
+		case o.pre == "":
+			return -1
+		}
+		return sign(preReleaseOrder[v.pre] - preReleaseOrder[o.pre])
+	}
+	if v.preLevel != o.preLevel {
+		return sign(v.preLevel - o.preLevel)
+	}
+	return sign(v.patch - o.patch)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// CompareVersions compara duas versões do Magento, retornando -1, 0 ou 1
+func CompareVersions(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	return va.compare(vb)
+}
+
+// SortVersions ordena versões do Magento da mais antiga para a mais recente
+func SortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool { return CompareVersions(versions[i], versions[j]) < 0 })
+}
+
+// ResolveConstraint retorna a versão suportada mais recente que satisfaz uma restrição do composer
+// (ex: "^2.4.7", "~2.4.7", "2.4.*", ">=2.4.7 <2.4.8", "2.4.8-p2 || 2.4.8-p3").
+// Versões estáveis têm preferência sobre pré-releases.
+func ResolveConstraint(constraint string) (string, error) {
+	matches, err := matchVersions(constraint, GetSupportedVersions())
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no supported Magento version satisfies %q (versions available: %s)", constraint, strings.Join(GetSupportedVersions(), ", "))
+	}
+
+	SortVersions(matches)
+	for i := len(matches) - 1; i >= 0; i-- {
+		if v, _ := parseVersion(matches[i]); v.pre == "" {
+			return matches[i], nil
+		}
+	}
+	return matches[len(matches)-1], nil
+}
+
+// matchVersions filtra as versões que satisfazem a restrição
+func matchVersions(constraint string, versions []string) ([]string, error) {
+	var alternatives [][]predicate
+	for _, alternative := range strings.Split(strings.ReplaceAll(constraint, "||", "|"), "|") {
+		predicates, err := parseConjunction(alternative)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+		alternatives = append(alternatives, predicates)
+	}
+
+	var matches []string
+	for _, candidate := range versions {
+		v, ok := parseVersion(candidate)
+		if !ok {
+			continue
+		}
+		for _, predicates := range alternatives {
+			if satisfiesAll(v, predicates) {
+				matches = append(matches, candidate)
+				break
+			}
+		}
+	}
+	return matches, nil
+}
+
+// predicate comparação de uma versão com um limite (ex: ">=", 2.4.7)
+type predicate struct {
+	op    string
+	bound version
+}
+
+func (p predicate) matches(v version) bool {
+	c := v.compare(p.bound)
+	switch p.op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case "!=":
+		return c != 0
+	}
+	return c == 0
+}
+
+func satisfiesAll(v version, predicates []predicate) bool {
+	for _, p := range predicates {
+		if !p.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseConjunction interpreta restrições separadas por espaço ou vírgula, que precisam ser todas satisfeitas
+func parseConjunction(s string) ([]predicate, error) {
+	// Remove flags de estabilidade (ex: 2.4.8@stable)
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' })
+	for i, field := range fields {
+		if at := strings.Index(field, "@"); at >= 0 {
+			fields[i] = field[:at]
+		}
+	}
+
+	var predicates []predicate
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if field == "" || field == "*" {
+			continue
+		}
+
+		// Intervalo com hífen: "2.4.7 - 2.4.8"
+		if i+2 < len(fields) && fields[i+1] == "-" {
+			low, err := parseBound(field)
+			if err != nil {
+				return nil, err
+			}
+			high, err := parseBound(fields[i+2])
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, predicate{">=", low}, predicate{"<=", high})
+			i += 2
+			continue
+		}
+
+		// Operador separado da versão: ">= 2.4.7"
+		if isOperator(field) && i+1 < len(fields) {
+			field += fields[i+1]
+			i++
+		}
+
+		parsed, err := parseConstraint(field)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, parsed...)
+	}
+	return predicates, nil
+}
+
+func isOperator(s string) bool {
+	switch s {
+	case ">", ">=", "<", "<=", "=", "==", "!=", "^", "~":
+		return true
+	}
+	return false
+}
+
+// parseConstraint interpreta uma única restrição (ex: "^2.4.7", "2.4.*", ">=2.4.7")
+func parseConstraint(s string) ([]predicate, error) {
+	for _, op := range []string{">=", "<=", "!=", "==", ">", "<", "="} {
+		if strings.HasPrefix(s, op) {
+			bound, err := parseBound(s[len(op):])
+			if err != nil {
+				return nil, err
+			}
+			if op == "==" {
+				op = "="
+			}
+			return []predicate{{op, bound}}, nil
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(s, "^"):
+		// ^2.4.7 aceita qualquer 2.x a partir de 2.4.7
+		low, err := parseBound(s[1:])
+		if err != nil {
+			return nil, err
+		}
+		high := version{}
+		switch {
+		case low.numbers[0] > 0:
+			high.numbers[0] = low.numbers[0] + 1
+		case low.numbers[1] > 0:
+			high.numbers[1] = low.numbers[1] + 1
+		default:
+			high.numbers[2] = low.numbers[2] + 1
+		}
+		return []predicate{{">=", low}, {"<", high}}, nil
+
+	case strings.HasPrefix(s, "~"):
+		// ~2.4.7 aceita 2.4.x a partir de 2.4.7; ~2.4 aceita 2.x a partir de 2.4
+		low, err := parseBound(s[1:])
+		if err != nil {
+			return nil, err
+		}
+		high := version{}
+		if segments := len(strings.Split(strings.SplitN(strings.TrimPrefix(s[1:], "v"), "-", 2)[0], ".")); segments <= 2 {
+			high.numbers[0] = low.numbers[0] + 1
+		} else {
+			high.numbers[0], high.numbers[1] = low.numbers[0], low.numbers[1]+1
+		}
+		return []predicate{{">=", low}, {"<", high}}, nil
+
+	case strings.HasSuffix(s, "-p*"):
+		// 2.4.8-p* aceita 2.4.8 e qualquer patch
+		low, err := parseBound(strings.TrimSuffix(s, "-p*"))
+		if err != nil {
+			return nil, err
+		}
+		high := low
+		high.numbers[2]++
+		return []predicate{{">=", low}, {"<", high}}, nil
+
+	case strings.Contains(s, "*") || strings.HasSuffix(s, ".x"):
+		// 2.4.* aceita qualquer 2.4.x
+		parts := strings.Split(s, ".")
+		var prefix []string
+		for _, part := range parts {
+			if part == "*" || part == "x" {
+				break
+			}
+			prefix = append(prefix, part)
+		}
+		if len(prefix) == 0 {
+			return nil, nil
+		}
+		if len(prefix) > len(version{}.numbers) {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		low, err := parseBound(strings.Join(prefix, "."))
+		if err != nil {
+			return nil, err
+		}
+		high := low
+		high.numbers[len(prefix)-1]++
+		return []predicate{{">=", low}, {"<", high}}, nil
+	}
+
+	bound, err := parseBound(s)
+	if err != nil {
+		return nil, err
+	}
+	return []predicate{{"=", bound}}, nil
+}
+
+func parseBound(s string) (version, error) {
+	v, ok := parseVersion(s)
+	if !ok {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	return v, nil
+}
+
+// mageOSLines versão do Magento Open Source em que cada série do Mage-OS é baseada.
+// Séries mais novas que as listadas usam a última linha conhecida.
+var mageOSLines = []struct {
+	series  string
+	magento string
+}{
+	{"1.0", "2.4.7"},
+	{"1.1", "2.4.8"},
+}
+
+// ResolveMageOS retorna a versão suportada do Magento equivalente a uma versão ou restrição do
+// Mage-OS (o patch mais recente da linha do Magento em que a série é baseada). Em restrições,
+// vale a primeira versão mencionada (ex: "^1.1" -> série 1.1).
+func ResolveMageOS(constraint string) (string, error) {
+	fields := strings.FieldsFunc(constraint, func(r rune) bool { return r == ' ' || r == ',' || r == '|' })
+	if len(fields) == 0 {
+		return "", fmt.Errorf("invalid Mage-OS version %q", constraint)
+	}
+	first := strings.TrimLeft(fields[0], "^~>=<!")
+	first = strings.TrimSuffix(strings.TrimSuffix(first, ".*"), ".x")
+
+	v, ok := parseVersion(first)
+	if !ok {
+		return "", fmt.Errorf("invalid Mage-OS version %q", constraint)
+	}
+
+	line := mageOSLines[len(mageOSLines)-1].magento
+	for _, l := range mageOSLines {
+		if series, _ := parseVersion(l.series); v.numbers[0] == series.numbers[0] && v.numbers[1] == series.numbers[1] {
+			line = l.magento
+			break
+		}
+	}
+	return ResolveConstraint(line + "-p*")
+}
@@ -0,0 +1,163 @@
+package magento
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2.4.8", "2.4.8", 0},
+		{"v2.4.8", "2.4.8", 0},
+		{"2.4.7", "2.4.8", -1},
+		{"2.4.10", "2.4.9", 1},
+		{"2.4.8-p1", "2.4.8", 1},
+		{"2.4.8-p10", "2.4.8-p9", 1},
+		{"2.4.9-alpha3", "2.4.9-beta1", -1},
+		{"2.4.9-beta2", "2.4.9-beta1", 1},
+		{"2.4.9-rc1", "2.4.9", -1},
+		{"2.4.9-beta1", "2.4.8-p3", 1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"2.4.8-p1", "2.4.10", "2.4.9-beta1", "2.4.7", "2.4.8", "2.4.9-alpha3", "2.4.7-p10", "2.4.7-p2"}
+	want := []string{"2.4.7", "2.4.7-p2", "2.4.7-p10", "2.4.8", "2.4.8-p1", "2.4.9-alpha3", "2.4.9-beta1", "2.4.10"}
+
+	SortVersions(versions)
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("SortVersions = %v, want %v", versions, want)
+	}
+}
+
+func TestResolveConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		// Versões exatas
+		{"2.4.8", "2.4.8"},
+		{"2.4.8-p2", "2.4.8-p2"},
+		{"v2.4.7-p1", "2.4.7-p1"},
+		{"=2.4.7", "2.4.7"},
+		{"2.4.8@stable", "2.4.8"},
+		{"2.4.9-beta1", "2.4.9-beta1"},
+
+		// Caret e til
+		{"^2.4.7", "2.4.8-p3"},
+		{"^2.4.8-p1", "2.4.8-p3"},
+		{"~2.4.7", "2.4.8-p3"},
+		{"~2.4", "2.4.8-p3"},
+		{"~2.4.7-p1", "2.4.8-p3"},
+
+		// Curingas e patches
+		{"*", "2.4.8-p3"},
+		{"2.4.*", "2.4.8-p3"},
+		{"2.4.x", "2.4.8-p3"},
+		{"2.4.7.*", "2.4.7-p3"},
+		{"2.4.7-p*", "2.4.7-p3"},
+		{"2.4.8-p*", "2.4.8-p3"},
+
+		// Comparações, conjunções e intervalos
+		{">=2.4.7 <2.4.8", "2.4.7-p3"},
+		{">=2.4.7, <2.4.8", "2.4.7-p3"},
+		{">= 2.4.7 < 2.4.8-p2", "2.4.8-p1"},
+		{"^2.4.7 !=2.4.8-p3", "2.4.8-p2"},
+		{"<=2.4.8", "2.4.8"},
+		{">2.4.8-p2", "2.4.8-p3"},
+		{"2.4.7 - 2.4.8", "2.4.8"},
+		{"2.4.7-p1 - 2.4.7-p2", "2.4.7-p2"},
+
+		// Alternativas
+		{"2.4.7-p1 || 2.4.7-p2", "2.4.7-p2"},
+		{"2.4.8-p1|2.4.7-p3", "2.4.8-p1"},
+		{"~2.4.7.0 || ^2.4.8", "2.4.8-p3"},
+
+		// Versões estáveis têm preferência; sem elas, a pré-release mais recente
+		{">=2.4.8", "2.4.8-p3"},
+		{">=2.4.9-alpha1", "2.4.9-beta1"},
+		{"2.4.9-alpha3 || 2.4.9-beta1", "2.4.9-beta1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := ResolveConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ResolveConstraint(%q): %v", tt.constraint, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveConstraint(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveConstraintErrors(t *testing.T) {
+	constraints := []string{
+		"2.4.6",
+		"~2.3.7",
+		"~2.4.6-p1 <2.4.7",
+		">=3.0",
+		"2.4.8-p9",
+		"latest",
+		"2.4.8-foo",
+		"2.4.8.1.*",
+	}
+
+	for _, constraint := range constraints {
+		if got, err := ResolveConstraint(constraint); err == nil {
+			t.Errorf("ResolveConstraint(%q) = %q, want an error", constraint, got)
+		}
+	}
+}
+
+func TestResolveMageOS(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"1.0.0", "2.4.7-p3"},
+		{"1.0.6", "2.4.7-p3"},
+		{"v1.0.4", "2.4.7-p3"},
+		{"^1.0", "2.4.7-p3"},
+		{"~1.0.2", "2.4.7-p3"},
+		{"1.0.*", "2.4.7-p3"},
+		{"1.1.0", "2.4.8-p3"},
+		{"~1.1.1", "2.4.8-p3"},
+		{">=1.1 <2.0", "2.4.8-p3"},
+		{"1.1.0 || 1.0.6", "2.4.8-p3"},
+
+		// Séries mais novas que as conhecidas usam a última linha
+		{"1.2.0", "2.4.8-p3"},
+		{"^2.0", "2.4.8-p3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := ResolveMageOS(tt.constraint)
+			if err != nil {
+				t.Fatalf("ResolveMageOS(%q): %v", tt.constraint, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveMageOS(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveMageOSErrors(t *testing.T) {
+	for _, constraint := range []string{"", "  ", "dev-main", "*"} {
+		if got, err := ResolveMageOS(constraint); err == nil {
+			t.Errorf("ResolveMageOS(%q) = %q, want an error", constraint, got)
+		}
+	}
+}
@@ -143,6 +143,7 @@ func GetSupportedVersions() []string {
 	for version := range versionCache {
 		versions = append(versions, version)
 	}
+	SortVersions(versions)
 	return versions
 }
 
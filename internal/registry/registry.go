@@ -0,0 +1,157 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Project representa um projeto registrado pelo 'deck setup'
+type Project struct {
+	Name    string         `yaml:"name"`
+	Path    string         `yaml:"path"`
+	Domains []string       `yaml:"domains,omitempty"`
+	Magento string         `yaml:"magento,omitempty"`
+	Ports   map[string]int `yaml:"ports,omitempty"` // portas TCP do host alocadas por serviço (ex: mariadb: 33061)
+}
+
+// Registry lista global de projetos conhecidos pelo Deck
+type Registry struct {
+	Projects []Project `yaml:"projects"`
+
+	path string
+}
+
+// Path retorna o caminho do arquivo de registro (ex: ~/.config/deck/projects.yaml)
+func Path() (string, error) {
+	dir, err := config.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "projects.yaml"), nil
+}
+
+// Load carrega o registro de projetos, retornando um registro vazio se o arquivo não existir
+func Load() (*Registry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	reg := &Registry{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project registry: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("failed to parse project registry %s: %w", path, err)
+	}
+
+	return reg, nil
+}
+
+// Save grava o registro de projetos em disco
+func (r *Registry) Save() error {
+	sort.Slice(r.Projects, func(i, j int) bool {
+		return r.Projects[i].Name < r.Projects[j].Name
+	})
+
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project registry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write project registry: %w", err)
+	}
+
+	return nil
+}
+
+// Get retorna o projeto com o nome informado ou nil se não estiver registrado
+func (r *Registry) Get(name string) *Project {
+	for i := range r.Projects {
+		if r.Projects[i].Name == name {
+			return &r.Projects[i]
+		}
+	}
+	return nil
+}
+
+// Register adiciona ou atualiza um projeto no registro
+func (r *Registry) Register(project Project) {
+	if existing := r.Get(project.Name); existing != nil {
+		*existing = project
+		return
+	}
+	r.Projects = append(r.Projects, project)
+}
+
+// AllocatePorts retorna as portas TCP do projeto para os serviços informados. Portas já alocadas
+// ao projeto são mantidas; as novas são a primeira porta a partir da base do serviço que não esteja
+// alocada a outro projeto e para a qual available retorne true.
+func (r *Registry) AllocatePorts(name string, services []string, available func(port int) bool) (map[string]int, error) {
+	used := map[int]bool{}
+	var current map[string]int
+	for _, project := range r.Projects {
+		if project.Name == name {
+			current = project.Ports
+			continue
+		}
+		for _, port := range project.Ports {
+			used[port] = true
+		}
+	}
+
+	ports := make(map[string]int, len(services))
+	for _, service := range services {
+		if port, ok := current[service]; ok && !used[port] {
+			ports[service] = port
+			used[port] = true
+			continue
+		}
+
+		tcp, ok := config.TCPServices[service]
+		if !ok {
+			return nil, fmt.Errorf("unknown TCP service %q", service)
+		}
+
+		port := tcp.HostPortBase
+		for ; port <= 65535; port++ {
+			if !used[port] && available(port) {
+				break
+			}
+		}
+		if port > 65535 {
+			return nil, fmt.Errorf("no free port available for %s", service)
+		}
+		ports[service] = port
+		used[port] = true
+	}
+
+	return ports, nil
+}
+
+// Remove remove um projeto do registro, retornando false se ele não existir
+func (r *Registry) Remove(name string) bool {
+	for i := range r.Projects {
+		if r.Projects[i].Name == name {
+			r.Projects = append(r.Projects[:i], r.Projects[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package traefik
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dashboardCertName nome do certificado do dashboard; o "_" evita colisão com nomes de projeto
+const dashboardCertName = "_dashboard"
+
+// dashboardPasswordFile arquivo, no diretório do Traefik, com a senha gerada para o dashboard
+const dashboardPasswordFile = "dashboard-password"
+
+// DashboardPassword retorna a senha do dashboard: a da configuração global ou, quando vazia,
+// uma senha aleatória gerada uma única vez e guardada no diretório do Traefik
+func DashboardPassword(global *config.GlobalConfig) (string, error) {
+	if global.Traefik.Dashboard.Password != "" {
+		return global.Traefik.Dashboard.Password, nil
+	}
+
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(traefikDir, dashboardPasswordFile)
+
+	if data, err := os.ReadFile(path); err == nil {
+		if password := strings.TrimSpace(string(data)); password != "" {
+			return password, nil
+		}
+	}
+
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate dashboard password: %w", err)
+	}
+	password := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := os.MkdirAll(traefikDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", traefikDir, err)
+	}
+	if err := os.WriteFile(path, []byte(password+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to save dashboard password: %w", err)
+	}
+
+	return password, nil
+}
+
+// DashboardPasswordSource descreve de onde vem a senha do dashboard, para exibição ao usuário
+func DashboardPasswordSource(global *config.GlobalConfig) string {
+	if global.Traefik.Dashboard.Password != "" {
+		return "traefik.dashboard.password in " + globalConfigHint()
+	}
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return dashboardPasswordFile
+	}
+	return filepath.Join(traefikDir, dashboardPasswordFile)
+}
+
+// dashboardUsersLabel label do docker-compose.yml com o usuário e o hash da senha do dashboard
+const dashboardUsersLabel = "traefik.http.middlewares.deck-dashboard-auth.basicauth.users="
+
+// dashboardUsers retorna o par usuário:hash do basicauth do dashboard, com os "$" do hash
+// escapados para o docker-compose. O hash do docker-compose.yml atual é reaproveitado enquanto
+// o usuário e a senha não mudam, já que o bcrypt gera um salt novo a cada chamada e o arquivo
+// mudaria (recriando o Traefik) em todo deck setup
+func dashboardUsers(global *config.GlobalConfig, password string) (string, error) {
+	user := global.Traefik.Dashboard.User
+
+	if hash := currentDashboardHash(user); hash != "" &&
+		bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+		return user + ":" + strings.ReplaceAll(hash, "$", "$$"), nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash dashboard password: %w", err)
+	}
+	return user + ":" + strings.ReplaceAll(string(hash), "$", "$$"), nil
+}
+
+// currentDashboardHash retorna o hash bcrypt do usuário no docker-compose.yml atual do Traefik, se houver
+func currentDashboardHash(user string) string {
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(traefikDir, "docker-compose.yml"))
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		_, value, found := strings.Cut(line, dashboardUsersLabel)
+		if !found {
+			continue
+		}
+		value = strings.ReplaceAll(strings.TrimSuffix(strings.TrimSpace(value), `"`), "$$", "$")
+		name, hash, found := strings.Cut(value, ":")
+		if found && name == user && strings.HasPrefix(hash, "$2") {
+			return hash
+		}
+	}
+	return ""
+}
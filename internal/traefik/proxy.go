@@ -0,0 +1,194 @@
+package traefik
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/caravelcommerce/deck/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ContainerName nome do container do Traefik compartilhado pelos projetos
+const ContainerName = "deck_traefik"
+
+// DeckVersion versão do Deck registrada ao aplicar a configuração do Traefik
+var DeckVersion = "dev"
+
+// stateFile arquivo, no diretório do Traefik, com o estado da configuração aplicada ao container
+const stateFile = ".deck-proxy.yaml"
+
+// proxyState configuração aplicada ao container na última vez que ele foi (re)criado
+type proxyState struct {
+	DeckVersion string `yaml:"deck_version"`
+	ConfigHash  string `yaml:"config_hash"`
+}
+
+// Status estado do Traefik compartilhado
+type Status struct {
+	Running       bool
+	Image         string   // imagem do container em execução
+	ConfiguredTag string   // imagem definida na configuração global
+	AppliedBy     string   // versão do Deck que aplicou a configuração em uso
+	StaleReasons  []string // motivos pelos quais o container não reflete a configuração atual
+}
+
+// Stale informa se o container precisa ser recriado para aplicar a configuração atual
+func (s *Status) Stale() bool {
+	return len(s.StaleReasons) > 0
+}
+
+// GetStatus compara o container em execução com a configuração global e a versão do Deck
+func GetStatus(global *config.GlobalConfig) (*Status, error) {
+	status := &Status{
+		Running:       IsTraefikRunning(),
+		ConfiguredTag: "traefik:" + global.Traefik.Version,
+	}
+
+	if out, err := exec.Command("docker", "inspect", "-f", "{{.Config.Image}}", ContainerName).Output(); err == nil {
+		status.Image = strings.TrimSpace(string(out))
+	}
+	if status.Image == "" {
+		return status, nil
+	}
+
+	compose, err := renderCompose(global)
+	if err != nil {
+		return nil, err
+	}
+
+	state := readState()
+	status.AppliedBy = state.DeckVersion
+
+	// A configuração gerada depende da versão do Deck e da configuração global
+	switch {
+	case state.ConfigHash == "":
+		status.StaleReasons = append(status.StaleReasons, "the container was created by an older Deck version")
+	case state.ConfigHash != hash(compose):
+		status.StaleReasons = append(status.StaleReasons, fmt.Sprintf("the configuration generated by Deck %s differs from the one applied by Deck %s", DeckVersion, state.DeckVersion))
+	}
+	if status.Image != status.ConfiguredTag {
+		status.StaleReasons = append(status.StaleReasons, fmt.Sprintf("running %s, configured %s", status.Image, status.ConfiguredTag))
+	}
+
+	return status, nil
+}
+
+// Start inicia o Traefik, verificando antes se as portas estão livres
+func Start(global *config.GlobalConfig) error {
+	if IsTraefikRunning() {
+		return nil
+	}
+	if err := CheckPorts(global); err != nil {
+		return err
+	}
+	if _, err := WriteConfig(global); err != nil {
+		return err
+	}
+	return up(global, "-d")
+}
+
+// Stop para o Traefik sem remover o container
+func Stop() error {
+	return compose("stop")
+}
+
+// Restart recria o container do Traefik com a configuração atual, verificando antes se as portas
+// que ele ainda não publica estão livres
+func Restart(global *config.GlobalConfig) error {
+	if err := CheckPorts(global); err != nil {
+		return err
+	}
+	if _, err := WriteConfig(global); err != nil {
+		return err
+	}
+	return up(global, "-d", "--force-recreate")
+}
+
+// Upgrade baixa a imagem configurada do Traefik e recria o container
+func Upgrade(global *config.GlobalConfig) error {
+	if err := CheckPorts(global); err != nil {
+		return err
+	}
+	if _, err := WriteConfig(global); err != nil {
+		return err
+	}
+	if err := compose("pull"); err != nil {
+		return fmt.Errorf("failed to pull the Traefik image: %w", err)
+	}
+	return up(global, "-d", "--force-recreate")
+}
+
+// Logs exibe os logs do Traefik
+func Logs(follow bool, tail string) error {
+	args := []string{"logs", "--tail", tail}
+	if follow {
+		args = append(args, "--follow")
+	}
+	return compose(args...)
+}
+
+// up executa 'docker compose up' e registra a configuração aplicada
+func up(global *config.GlobalConfig, args ...string) error {
+	if err := compose(append([]string{"up"}, args...)...); err != nil {
+		return fmt.Errorf("failed to start Traefik: %w", err)
+	}
+
+	composeFile, err := renderCompose(global)
+	if err != nil {
+		return err
+	}
+	return writeState(proxyState{DeckVersion: DeckVersion, ConfigHash: hash(composeFile)})
+}
+
+// compose executa um comando docker compose no diretório do Traefik
+func compose(args ...string) error {
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("docker", append([]string{"compose"}, args...)...)
+	cmd.Dir = traefikDir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func readState() proxyState {
+	var state proxyState
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(filepath.Join(traefikDir, stateFile))
+	if err != nil {
+		return state
+	}
+	_ = yaml.Unmarshal(data, &state)
+	return state
+}
+
+func writeState(state proxyState) error {
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode proxy state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(traefikDir, stateFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to save proxy state: %w", err)
+	}
+	return nil
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -1,36 +1,80 @@
 package traefik
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/caravelcommerce/deck/internal/certs"
+	"github.com/caravelcommerce/deck/internal/config"
+	"github.com/caravelcommerce/deck/internal/registry"
 )
 
+// traefikDockerCompose template do docker-compose.yml do Traefik, renderizado com a configuração global
 const traefikDockerCompose = `version: '3.8'
 
 services:
   traefik:
-    image: traefik:v3.0
+    image: traefik:{{.Version}}
     container_name: deck_traefik
     command:
-      - "--api.insecure=true"
       - "--providers.docker=true"
       - "--providers.docker.exposedbydefault=false"
+      - "--providers.docker.network=traefik_network"
       - "--providers.file.directory=/etc/traefik/dynamic"
       - "--providers.file.watch=true"
       - "--entrypoints.web.address=:80"
       - "--entrypoints.websecure.address=:443"
-      - "--entrypoints.web.http.redirections.entrypoint.to=websecure"
+      - "--entrypoints.web.http.redirections.entrypoint.to=:{{.HTTPSPort}}"
       - "--entrypoints.web.http.redirections.entrypoint.scheme=https"
+{{- range .TCPEntrypoints}}
+      - "--entrypoints.{{.Name}}.address=:{{.Port}}"
+{{- end}}
+{{- if .Dashboard}}
+      - "--api.dashboard=true"
+{{- if .DashboardPort}}
+      - "--entrypoints.dashboard.address=:8080"
+{{- end}}
+{{- end}}
     ports:
-      - "80:80"
-      - "443:443"
-      - "8080:8080"
+      - "{{.BindAddress}}:{{.HTTPPort}}:80"
+      - "{{.BindAddress}}:{{.HTTPSPort}}:443"
+{{- range .TCPEntrypoints}}
+      - "{{$.BindAddress}}:{{.Port}}:{{.Port}}"
+{{- end}}
+{{- if and .Dashboard .DashboardPort}}
+      - "{{.BindAddress}}:{{.DashboardPort}}:8080"
+{{- end}}
     volumes:
       - /var/run/docker.sock:/var/run/docker.sock:ro
       - ./certs:/etc/traefik/certs:ro
       - ./dynamic:/etc/traefik/dynamic:ro
+{{- if .Dashboard}}
+    labels:
+      - "traefik.enable=true"
+      - "` + dashboardUsersLabel + `{{.DashboardUsers}}"
+      - "traefik.http.routers.deck-dashboard.rule=Host(` + "`{{.DashboardHost}}`" + `)"
+      - "traefik.http.routers.deck-dashboard.entrypoints=websecure"
+      - "traefik.http.routers.deck-dashboard.tls=true"
+      - "traefik.http.routers.deck-dashboard.service=api@internal"
+      - "traefik.http.routers.deck-dashboard.middlewares=deck-dashboard-auth"
+{{- if .DashboardPort}}
+      - "traefik.http.routers.deck-dashboard-port.rule=PathPrefix(` + "`/`" + `)"
+      - "traefik.http.routers.deck-dashboard-port.entrypoints=dashboard"
+      - "traefik.http.routers.deck-dashboard-port.service=api@internal"
+      - "traefik.http.routers.deck-dashboard-port.middlewares=deck-dashboard-auth"
+{{- end}}
+{{- end}}
     networks:
       - traefik_network
     restart: unless-stopped
@@ -41,24 +85,202 @@ networks:
     driver: bridge
 `
 
-const traefikDynamicConfig = `tls:
-  certificates:
-    - certFile: /etc/traefik/certs/local-cert.pem
-      keyFile: /etc/traefik/certs/local-key.pem
-  stores:
-    default:
-      defaultCertificate:
-        certFile: /etc/traefik/certs/local-cert.pem
-        keyFile: /etc/traefik/certs/local-key.pem
-`
+// portDialTimeout tempo máximo de espera ao testar se uma porta privilegiada está em uso
+const portDialTimeout = 500 * time.Millisecond
 
-func SetupTraefik() error {
-	homeDir, err := os.UserHomeDir()
+// composeData dados usados para renderizar o docker-compose.yml do Traefik
+type composeData struct {
+	Version        string
+	BindAddress    string
+	HTTPPort       int
+	HTTPSPort      int
+	Dashboard      bool
+	DashboardHost  string
+	DashboardPort  int
+	DashboardUsers string
+	TCPEntrypoints []tcpEntrypoint
+}
+
+// tcpEntrypoint porta TCP alocada a um serviço de um projeto (ex: demo-mariadb em 33061)
+type tcpEntrypoint struct {
+	Name string
+	Port int
+}
+
+// tcpEntrypoints retorna as portas TCP alocadas aos projetos registrados, ordenadas por porta
+func tcpEntrypoints() ([]tcpEntrypoint, error) {
+	reg, err := registry.Load()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
+	}
+
+	var entrypoints []tcpEntrypoint
+	for _, project := range reg.Projects {
+		for service, port := range project.Ports {
+			entrypoints = append(entrypoints, tcpEntrypoint{Name: project.Name + "-" + service, Port: port})
+		}
+	}
+	sort.Slice(entrypoints, func(i, j int) bool {
+		return entrypoints[i].Port < entrypoints[j].Port
+	})
+	return entrypoints, nil
+}
+
+// renderCompose gera o docker-compose.yml do Traefik a partir da configuração global
+func renderCompose(global *config.GlobalConfig) ([]byte, error) {
+	data := composeData{
+		Version:     global.Traefik.Version,
+		BindAddress: global.Traefik.BindAddress,
+		HTTPPort:    global.Traefik.HTTPPort,
+		HTTPSPort:   global.Traefik.HTTPSPort,
+		Dashboard:   global.DashboardEnabled(),
 	}
 
-	traefikDir := filepath.Join(homeDir, ".deck-traefik")
+	entrypoints, err := tcpEntrypoints()
+	if err != nil {
+		return nil, err
+	}
+	data.TCPEntrypoints = entrypoints
+
+	if data.Dashboard {
+		password, err := DashboardPassword(global)
+		if err != nil {
+			return nil, err
+		}
+		data.DashboardHost = global.Traefik.Dashboard.Host
+		data.DashboardPort = global.Traefik.Dashboard.Port
+		users, err := dashboardUsers(global, password)
+		if err != nil {
+			return nil, err
+		}
+		data.DashboardUsers = users
+	}
+
+	tmpl, err := template.New("docker-compose.yml").Parse(traefikDockerCompose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse traefik docker-compose template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render traefik docker-compose.yml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CheckPorts verifica se as portas configuradas para o Traefik estão livres. Portas já publicadas
+// pelo container em execução são ignoradas, pois ele as libera ao ser recriado.
+func CheckPorts(global *config.GlobalConfig) error {
+	published := publishedPorts()
+
+	ports := []struct {
+		field string
+		value int
+	}{
+		{"http_port", global.Traefik.HTTPPort},
+		{"https_port", global.Traefik.HTTPSPort},
+	}
+	if global.DashboardEnabled() && global.Traefik.Dashboard.Port != 0 {
+		ports = append(ports, struct {
+			field string
+			value int
+		}{"dashboard.port", global.Traefik.Dashboard.Port})
+	}
+
+	for _, port := range ports {
+		if !published[port.value] && !PortAvailable(global, port.value) {
+			return fmt.Errorf("port %d on %s is already in use; stop the process using it or set traefik.%s in %s",
+				port.value, global.Traefik.BindAddress, port.field, globalConfigHint())
+		}
+	}
+
+	entrypoints, err := tcpEntrypoints()
+	if err != nil {
+		return err
+	}
+	for _, entrypoint := range entrypoints {
+		if !published[entrypoint.Port] && !PortAvailable(global, entrypoint.Port) {
+			return fmt.Errorf("port %d on %s, allocated to %s, is already in use; stop the process using it",
+				entrypoint.Port, global.Traefik.BindAddress, entrypoint.Name)
+		}
+	}
+
+	return nil
+}
+
+// publishedPorts retorna as portas do host publicadas pelo container do Traefik em execução
+func publishedPorts() map[int]bool {
+	published := map[int]bool{}
+	if !IsTraefikRunning() {
+		return published
+	}
+
+	// Cada linha tem o formato "3306/tcp -> 127.0.0.1:33061"
+	output, err := exec.Command("docker", "port", ContainerName).Output()
+	if err != nil {
+		return published
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		_, host, found := strings.Cut(line, "->")
+		if !found {
+			continue
+		}
+		host = strings.TrimSpace(host)
+		if port, err := strconv.Atoi(host[strings.LastIndex(host, ":")+1:]); err == nil {
+			published[port] = true
+		}
+	}
+	return published
+}
+
+// PortAvailable informa se a porta está livre no endereço em que o Traefik publica suas portas
+func PortAvailable(global *config.GlobalConfig, port int) bool {
+	address := net.JoinHostPort(global.Traefik.BindAddress, strconv.Itoa(port))
+	listener, err := net.Listen("tcp", address)
+	if err == nil {
+		listener.Close()
+		return true
+	}
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return false
+	}
+	if errors.Is(err, syscall.EACCES) || errors.Is(err, syscall.EPERM) {
+		// Sem permissão para portas privilegiadas não dá para testar com Listen (o Docker ainda
+		// consegue publicá-las); a porta é considerada ocupada se algo aceitar conexões nela
+		return !portListening(global.Traefik.BindAddress, port)
+	}
+	return true
+}
+
+// portListening informa se algum processo aceita conexões TCP na porta do endereço informado
+func portListening(bindAddress string, port int) bool {
+	host := bindAddress
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), portDialTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// globalConfigHint retorna o caminho da configuração global para mensagens de erro
+func globalConfigHint() string {
+	path, err := config.GlobalConfigPath()
+	if err != nil {
+		return config.GlobalConfigFile
+	}
+	return path
+}
+
+// WriteConfig gera os arquivos do Traefik, gravando o docker-compose.yml apenas quando ele muda
+func WriteConfig(global *config.GlobalConfig) (bool, error) {
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return false, err
+	}
 
 	// Create Traefik directory structure
 	dirs := []string{
@@ -69,42 +291,47 @@ func SetupTraefik() error {
 
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+			return false, fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
 
 	// Generate docker-compose.yml
-	composePath := filepath.Join(traefikDir, "docker-compose.yml")
-	if err := os.WriteFile(composePath, []byte(traefikDockerCompose), 0644); err != nil {
-		return fmt.Errorf("failed to create traefik docker-compose.yml: %w", err)
+	compose, err := renderCompose(global)
+	if err != nil {
+		return false, err
 	}
-
-	// Generate dynamic config
-	dynamicPath := filepath.Join(traefikDir, "dynamic", "tls.yml")
-	if err := os.WriteFile(dynamicPath, []byte(traefikDynamicConfig), 0644); err != nil {
-		return fmt.Errorf("failed to create traefik dynamic config: %w", err)
+	composePath := filepath.Join(traefikDir, "docker-compose.yml")
+	current, err := os.ReadFile(composePath)
+	changed := err != nil || !bytes.Equal(current, compose)
+	if changed {
+		if err := os.WriteFile(composePath, compose, 0644); err != nil {
+			return false, fmt.Errorf("failed to create traefik docker-compose.yml: %w", err)
+		}
 	}
 
-	// Generate SSL certificates
-	if err := generateSSLCerts(filepath.Join(traefikDir, "certs")); err != nil {
-		return fmt.Errorf("failed to generate SSL certificates: %w", err)
+	// Certificate for the dashboard hostname
+	if global.DashboardEnabled() {
+		if err := EnsureProjectCertificate(dashboardCertName, []string{global.Traefik.Dashboard.Host}); err != nil {
+			return false, err
+		}
 	}
 
-	// Start Traefik
-	cmd := exec.Command("docker", "compose", "up", "-d")
-	cmd.Dir = traefikDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to start Traefik: %w", err)
+	// Remove the shared self-signed certificate used by older versions
+	for _, path := range []string{
+		filepath.Join(traefikDir, "dynamic", "tls.yml"),
+		filepath.Join(traefikDir, "certs", "local-cert.pem"),
+		filepath.Join(traefikDir, "certs", "local-key.pem"),
+	} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
 	}
 
-	return nil
+	return changed, nil
 }
 
 func IsTraefikRunning() bool {
-	cmd := exec.Command("docker", "ps", "--filter", "name=deck_traefik", "--format", "{{.Names}}")
+	cmd := exec.Command("docker", "ps", "--filter", "name="+ContainerName, "--format", "{{.Names}}")
 	output, err := cmd.Output()
 	if err != nil {
 		return false
@@ -112,29 +339,72 @@ func IsTraefikRunning() bool {
 	return len(output) > 0
 }
 
-func generateSSLCerts(certsDir string) error {
-	certPath := filepath.Join(certsDir, "local-cert.pem")
-	keyPath := filepath.Join(certsDir, "local-key.pem")
+// projectDynamicConfig registra o certificado de um projeto no Traefik
+const projectDynamicConfig = `tls:
+  certificates:
+    - certFile: /etc/traefik/certs/%[1]s-cert.pem
+      keyFile: /etc/traefik/certs/%[1]s-key.pem
+`
+
+// EnsureProjectCertificate gera o certificado do projeto cobrindo todos os hostnames informados.
+// O certificado é emitido pela CA local e só é renovado quando os hostnames mudam ou ele está perto de expirar.
+func EnsureProjectCertificate(project string, hosts []string) error {
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return err
+	}
+
+	certsDir := filepath.Join(traefikDir, "certs")
+	dynamicDir := filepath.Join(traefikDir, "dynamic")
+	for _, dir := range []string{certsDir, dynamicDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	certPath := filepath.Join(certsDir, project+"-cert.pem")
+	keyPath := filepath.Join(certsDir, project+"-key.pem")
+
+	global, err := config.LoadGlobalConfig()
+	if err != nil {
+		return err
+	}
 
-	// Check if certificates already exist
-	if _, err := os.Stat(certPath); err == nil {
-		return nil
+	ca, _, err := certs.LoadOrCreateCA(global.Certificates.KeyType)
+	if err != nil {
+		return fmt.Errorf("failed to load local CA: %w", err)
 	}
 
-	// Generate self-signed certificate for *.test domain
-	cmd := exec.Command("openssl", "req", "-x509", "-newkey", "rsa:4096",
-		"-keyout", keyPath,
-		"-out", certPath,
-		"-days", "365",
-		"-nodes",
-		"-subj", "/CN=*.test/O=Deck Local Development",
-		"-addext", "subjectAltName=DNS:*.test,DNS:test")
+	if ca.NeedsRenewal(certPath, hosts) {
+		if err := ca.Issue(hosts, certPath, keyPath); err != nil {
+			return fmt.Errorf("failed to issue certificate for %s: %w", project, err)
+		}
+	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	dynamicPath := filepath.Join(dynamicDir, project+".yml")
+	if err := os.WriteFile(dynamicPath, []byte(fmt.Sprintf(projectDynamicConfig, project)), 0644); err != nil {
+		return fmt.Errorf("failed to create traefik dynamic config for %s: %w", project, err)
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to generate SSL certificate: %w", err)
+	return nil
+}
+
+// RemoveProjectCertificate remove o certificado e a configuração TLS do projeto
+func RemoveProjectCertificate(project string) error {
+	traefikDir, err := GetTraefikDir()
+	if err != nil {
+		return err
+	}
+
+	paths := []string{
+		filepath.Join(traefikDir, "dynamic", project+".yml"),
+		filepath.Join(traefikDir, "certs", project+"-cert.pem"),
+		filepath.Join(traefikDir, "certs", project+"-key.pem"),
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
 	}
 
 	return nil